@@ -0,0 +1,180 @@
+// Package cache provides a persistent memoization of target functions.
+//
+// The values of a target are stored on disk keyed on the exact coordinates of
+// the nodes. A target wrapped by a cache is invoked only at the nodes that have
+// not been seen before, possibly in earlier runs, which makes reruns with, for
+// instance, tighter accuracy pay only for the new nodes.
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"sync"
+
+	"github.com/ready-steady/adapt/algorithm"
+)
+
+var (
+	magic = [4]byte{'A', 'D', 'C', 'C'}
+)
+
+// Cache is a persistent store of the values of a target.
+type Cache struct {
+	ni uint
+	no uint
+
+	file   *os.File
+	values map[string][]float64
+	mutex  sync.RWMutex
+}
+
+// Open opens a cache stored in a file, creating the file if needed.
+func Open(path string, inputs, outputs uint) (*Cache, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	cache := &Cache{
+		ni: inputs,
+		no: outputs,
+
+		file:   file,
+		values: make(map[string][]float64),
+	}
+	if err = cache.load(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return cache, nil
+}
+
+// Close closes the file of the cache.
+func (self *Cache) Close() error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	return self.file.Close()
+}
+
+// Len returns the number of stored nodes.
+func (self *Cache) Len() uint {
+	self.mutex.RLock()
+	defer self.mutex.RUnlock()
+	return uint(len(self.values))
+}
+
+// Wrap creates a target that looks up the values of another target in the
+// cache and stores the ones that are missing. The wrapped target panics if the
+// values cannot be stored.
+func (self *Cache) Wrap(target algorithm.Target) algorithm.Target {
+	return func(node, value []float64) {
+		key := self.key(node)
+		if self.get(key, value) {
+			return
+		}
+		target(node, value)
+		if err := self.set(key, node, value); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// WrapContext is the same as Wrap for targets that can fail and be cancelled.
+// Failed evaluations are not stored.
+func (self *Cache) WrapContext(target algorithm.ContextTarget) algorithm.ContextTarget {
+	return func(ctx context.Context, node, value []float64) error {
+		key := self.key(node)
+		if self.get(key, value) {
+			return nil
+		}
+		if err := target(ctx, node, value); err != nil {
+			return err
+		}
+		return self.set(key, node, value)
+	}
+}
+
+func (self *Cache) get(key string, value []float64) bool {
+	self.mutex.RLock()
+	defer self.mutex.RUnlock()
+	stored, found := self.values[key]
+	if found {
+		copy(value, stored)
+	}
+	return found
+}
+
+func (self *Cache) set(key string, node, value []float64) error {
+	record := encode(append(append([]float64(nil), node...), value...))
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	if _, found := self.values[key]; found {
+		return nil
+	}
+	if _, err := self.file.Write(record); err != nil {
+		return err
+	}
+	self.values[key] = append([]float64(nil), value...)
+	return nil
+}
+
+func (self *Cache) key(node []float64) string {
+	return string(encode(node))
+}
+
+func (self *Cache) load() error {
+	data, err := io.ReadAll(self.file)
+	if err != nil {
+		return err
+	}
+
+	header := &bytes.Buffer{}
+	header.Write(magic[:])
+	var buffer [binary.MaxVarintLen64]byte
+	header.Write(buffer[:binary.PutUvarint(buffer[:], uint64(self.ni))])
+	header.Write(buffer[:binary.PutUvarint(buffer[:], uint64(self.no))])
+
+	if len(data) == 0 {
+		_, err = self.file.Write(header.Bytes())
+		return err
+	}
+	if !bytes.HasPrefix(data, header.Bytes()) {
+		return errors.New("the file is not a cache with the given dimensions")
+	}
+
+	ni, no := self.ni, self.no
+	size := int(8 * (ni + no))
+	offset := header.Len()
+	for ; offset+size <= len(data); offset += size {
+		record := decode(data[offset : offset+size])
+		self.values[string(data[offset:offset+int(8*ni)])] = record[ni:]
+	}
+	if offset < len(data) {
+		// The last record is incomplete due to an interruption.
+		if err = self.file.Truncate(int64(offset)); err != nil {
+			return err
+		}
+	}
+	_, err = self.file.Seek(int64(offset), io.SeekStart)
+	return err
+}
+
+func decode(data []byte) []float64 {
+	values := make([]float64, len(data)/8)
+	for i := range values {
+		values[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[8*i:]))
+	}
+	return values
+}
+
+func encode(values []float64) []byte {
+	data := make([]byte, 8*len(values))
+	for i, value := range values {
+		binary.LittleEndian.PutUint64(data[8*i:], math.Float64bits(value))
+	}
+	return data
+}
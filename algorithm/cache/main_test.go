@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ready-steady/adapt/algorithm"
+	"github.com/ready-steady/adapt/algorithm/local"
+	"github.com/ready-steady/adapt/basis/polynomial"
+	"github.com/ready-steady/adapt/grid/equidistant"
+	"github.com/ready-steady/assert"
+)
+
+func TestCompute(t *testing.T) {
+	const (
+		ni = 2
+		no = 1
+	)
+
+	count := int64(0)
+	target := func(x, y []float64) {
+		atomic.AddInt64(&count, 1)
+		y[0] = math.Exp(-10.0*x[0]*x[0]) * math.Sin(5.0*x[1])
+	}
+
+	grid, basis := equidistant.NewClosed(ni), polynomial.NewClosed(ni, 1)
+	compute := func(path string, εs float64) *algorithm.Surrogate {
+		cache, err := Open(path, ni, no)
+		assert.Equal(err, nil, t)
+		defer cache.Close()
+		algorithm := local.New(ni, no, grid, basis)
+		strategy := local.NewStrategy(ni, no, grid, 1, 10, εs)
+		return algorithm.Compute(cache.Wrap(target), strategy)
+	}
+
+	path := filepath.Join(t.TempDir(), "cache")
+
+	surrogate := compute(path, 1e-2)
+	assert.Equal(count, int64(surrogate.Nodes), t)
+
+	count = 0
+	surrogate = compute(path, 1e-2)
+	assert.Equal(count, int64(0), t)
+
+	count = 0
+	previous := surrogate.Nodes
+	surrogate = compute(path, 1e-3)
+	assert.Equal(count, int64(surrogate.Nodes-previous), t)
+
+	cache, err := Open(path, ni, no)
+	assert.Equal(err, nil, t)
+	assert.Equal(cache.Len(), surrogate.Nodes, t)
+	cache.Close()
+
+	_, err = Open(path, ni, 2)
+	assert.Equal(err != nil, true, t)
+}
+
+func TestOpenIncomplete(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache")
+
+	cache, err := Open(path, 1, 1)
+	assert.Equal(err, nil, t)
+	target := cache.Wrap(func(x, y []float64) { y[0] = 2.0 * x[0] })
+	value := make([]float64, 1)
+	target([]float64{0.25}, value)
+	target([]float64{0.5}, value)
+	cache.Close()
+
+	info, err := os.Stat(path)
+	assert.Equal(err, nil, t)
+	assert.Equal(os.Truncate(path, info.Size()-3), nil, t)
+
+	cache, err = Open(path, 1, 1)
+	assert.Equal(err, nil, t)
+	assert.Equal(cache.Len(), uint(1), t)
+
+	failure := errors.New("failure")
+	err = cache.WrapContext(func(_ context.Context, _, _ []float64) error {
+		return failure
+	})(context.Background(), []float64{0.5}, value)
+	assert.Equal(err, failure, t)
+	assert.Equal(cache.Len(), uint(1), t)
+
+	target = cache.Wrap(func(x, y []float64) { y[0] = 2.0 * x[0] })
+	target([]float64{0.5}, value)
+	assert.Equal(value, []float64{1.0}, t)
+	cache.Close()
+
+	cache, err = Open(path, 1, 1)
+	assert.Equal(err, nil, t)
+	assert.Equal(cache.Len(), uint(2), t)
+	cache.Close()
+}
@@ -0,0 +1,62 @@
+package checkpoint
+
+import (
+	"encoding/gob"
+	"os"
+	"path/filepath"
+)
+
+// Checkpoint is a record of the values of a target computed so far.
+type Checkpoint struct {
+	Inputs  uint // Number of inputs
+	Outputs uint // Number of outputs
+
+	Nodes  []float64 // Nodes in the order they were requested
+	Values []float64 // Values of the target at the nodes
+}
+
+// Load reads a checkpoint from a file.
+func Load(path string) (*Checkpoint, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	checkpoint := &Checkpoint{}
+	if err = gob.NewDecoder(file).Decode(checkpoint); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+// Count returns the number of recorded nodes.
+func (self *Checkpoint) Count() uint {
+	if self.Inputs == 0 {
+		return 0
+	}
+	return uint(len(self.Nodes)) / self.Inputs
+}
+
+// Save writes a checkpoint to a file. The file is replaced atomically so that
+// a previous checkpoint survives if the writing gets interrupted.
+func (self *Checkpoint) Save(path string) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if err = gob.NewEncoder(file).Encode(self); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
@@ -0,0 +1,136 @@
+// Package checkpoint provides checkpointing of interpolation runs.
+//
+// A checkpoint is a record of the values of the target computed so far, in the
+// order they were requested. Since strategies are deterministic, the state of
+// an interrupted run, including the internals of its strategy, is restored
+// exactly by replaying the recorded values, which avoids invoking the target
+// again. After the replay, the run continues as if it had never been
+// interrupted, and the final interpolant is the same.
+//
+// The replay is exact only if the decisions of the strategy depend solely on
+// the values of the target. It is not the case when the running time is
+// limited, since the time at which the limit is reached cannot be reproduced;
+// therefore, strategies whose running time is limited, as reported by
+// algorithm.Timer, are rejected. Limits on the number of nodes are supported.
+package checkpoint
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ready-steady/adapt/algorithm"
+)
+
+// Process is a step-wise interpolation process.
+type Process interface {
+	Ask() ([]uint, []float64)
+	Tell([]uint, []float64) error
+	Done() bool
+	Strategy() algorithm.Strategy
+	Surrogate() *algorithm.Surrogate
+}
+
+// Runner drives interpolation processes and periodically writes checkpoints.
+type Runner struct {
+	path   string
+	period time.Duration
+}
+
+// New creates a runner writing checkpoints to a file. A checkpoint is written
+// once the values of an iteration have been computed and at least the given
+// period has elapsed since the previous checkpoint, and also when the process
+// finishes.
+func New(path string, period time.Duration) *Runner {
+	return &Runner{
+		path:   path,
+		period: period,
+	}
+}
+
+// Compute drives a process to completion from scratch.
+func (self *Runner) Compute(process Process, target algorithm.Target) (*algorithm.Surrogate, error) {
+	if err := check(process); err != nil {
+		return nil, err
+	}
+	surrogate := process.Surrogate()
+	checkpoint := &Checkpoint{Inputs: surrogate.Inputs, Outputs: surrogate.Outputs}
+	return self.run(process, target, checkpoint, 0)
+}
+
+// Resume drives a process to completion starting from the checkpoint stored in
+// the file. The process should be a fresh one created using the same
+// algorithm and strategy configuration as the interrupted process.
+func (self *Runner) Resume(process Process, target algorithm.Target) (*algorithm.Surrogate, error) {
+	if err := check(process); err != nil {
+		return nil, err
+	}
+	checkpoint, err := Load(self.path)
+	if err != nil {
+		return nil, err
+	}
+	surrogate := process.Surrogate()
+	if checkpoint.Inputs != surrogate.Inputs || checkpoint.Outputs != surrogate.Outputs {
+		return nil, errors.New("the checkpoint does not match the process")
+	}
+	return self.run(process, target, checkpoint, checkpoint.Count())
+}
+
+func (self *Runner) run(process Process, target algorithm.Target, checkpoint *Checkpoint,
+	nr uint) (*algorithm.Surrogate, error) {
+
+	ni, no := checkpoint.Inputs, checkpoint.Outputs
+	last := time.Now()
+	for k := uint(0); !process.Done(); {
+		positions, nodes := process.Ask()
+		nn := uint(len(positions))
+		if nr > k {
+			nn = min(nn, nr-k)
+			if !equal(nodes[:nn*ni], checkpoint.Nodes[k*ni:(k+nn)*ni]) {
+				return nil, errors.New("the checkpoint does not match the process")
+			}
+			if err := process.Tell(positions[:nn], checkpoint.Values[k*no:(k+nn)*no]); err != nil {
+				return nil, err
+			}
+			k += nn
+			continue
+		}
+		values := algorithm.Invoke(target, nodes, ni, no)
+		checkpoint.Nodes = append(checkpoint.Nodes, nodes...)
+		checkpoint.Values = append(checkpoint.Values, values...)
+		if err := process.Tell(positions, values); err != nil {
+			return nil, err
+		}
+		k += nn
+		nr = k
+		if process.Done() || time.Since(last) >= self.period {
+			if err := checkpoint.Save(self.path); err != nil {
+				return nil, err
+			}
+			last = time.Now()
+		}
+	}
+	return process.Surrogate(), nil
+}
+
+func check(process Process) error {
+	if timer, ok := process.Strategy().(algorithm.Timer); ok && timer.Timed() {
+		return errors.New("checkpointing does not support limits on the running time")
+	}
+	return nil
+}
+
+func equal(one, two []float64) bool {
+	for i := range one {
+		if one[i] != two[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func min(one, two uint) uint {
+	if one < two {
+		return one
+	}
+	return two
+}
@@ -0,0 +1,107 @@
+package checkpoint
+
+import (
+	"math"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ready-steady/adapt/algorithm/global"
+	"github.com/ready-steady/adapt/algorithm/local"
+	"github.com/ready-steady/adapt/basis/polynomial"
+	"github.com/ready-steady/adapt/grid/equidistant"
+	"github.com/ready-steady/assert"
+)
+
+func TestResumeGlobal(t *testing.T) {
+	const (
+		ni = 2
+		no = 1
+	)
+
+	grid, basis := equidistant.NewClosed(ni), polynomial.NewClosed(ni, 1)
+	start := func() Process {
+		algorithm := global.New(ni, no, grid, basis)
+		return algorithm.Start(global.NewStrategy(ni, no, grid, 1, 10, 1e-4, 1e-3))
+	}
+
+	testResume(start, t)
+}
+
+func TestResumeLocal(t *testing.T) {
+	const (
+		ni = 2
+		no = 1
+	)
+
+	grid, basis := equidistant.NewClosed(ni), polynomial.NewClosed(ni, 1)
+	start := func() Process {
+		algorithm := local.New(ni, no, grid, basis)
+		return algorithm.Start(local.NewStrategy(ni, no, grid, 1, 10, 1e-3))
+	}
+
+	testResume(start, t)
+}
+
+func TestRejectTimed(t *testing.T) {
+	const (
+		ni = 2
+		no = 1
+	)
+
+	grid, basis := equidistant.NewClosed(ni), polynomial.NewClosed(ni, 1)
+	algorithm := local.New(ni, no, grid, basis)
+	target := func(x, y []float64) {
+		y[0] = x[0] + x[1]
+	}
+
+	path := filepath.Join(t.TempDir(), "checkpoint")
+	runner := New(path, time.Duration(0))
+
+	strategy := local.NewStrategy(ni, no, grid, 1, 10, 1e-3)
+	strategy.Limit(100, time.Duration(0))
+	_, err := runner.Compute(algorithm.Start(strategy), target)
+	assert.Equal(err, nil, t)
+
+	strategy = local.NewStrategy(ni, no, grid, 1, 10, 1e-3)
+	strategy.Limit(100, time.Hour)
+	_, err = runner.Compute(algorithm.Start(strategy), target)
+	assert.Equal(err != nil, true, t)
+	_, err = runner.Resume(algorithm.Start(strategy), target)
+	assert.Equal(err != nil, true, t)
+}
+
+func testResume(start func() Process, t *testing.T) {
+	count := int64(0)
+	target := func(x, y []float64) {
+		atomic.AddInt64(&count, 1)
+		y[0] = math.Exp(-10.0*x[0]*x[0]) * math.Sin(5.0*x[1])
+	}
+
+	path := filepath.Join(t.TempDir(), "checkpoint")
+	runner := New(path, time.Duration(0))
+
+	expected, err := runner.Compute(start(), target)
+	assert.Equal(err, nil, t)
+	total := count
+
+	checkpoint, err := Load(path)
+	assert.Equal(err, nil, t)
+	assert.Equal(checkpoint.Count(), uint(total), t)
+
+	nr := checkpoint.Count() / 2
+	checkpoint.Nodes = checkpoint.Nodes[:nr*checkpoint.Inputs]
+	checkpoint.Values = checkpoint.Values[:nr*checkpoint.Outputs]
+	assert.Equal(checkpoint.Save(path), nil, t)
+
+	count = 0
+	surrogate, err := runner.Resume(start(), target)
+	assert.Equal(err, nil, t)
+	assert.Equal(surrogate, expected, t)
+	assert.Equal(count, total-int64(nr), t)
+
+	checkpoint, err = Load(path)
+	assert.Equal(err, nil, t)
+	assert.Equal(checkpoint.Count(), uint(total), t)
+}
@@ -0,0 +1,231 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/ready-steady/adapt/algorithm"
+)
+
+var (
+	magic = [4]byte{'A', 'D', 'P', 'T'}
+)
+
+// MarshalBinary encodes a model using the binary format.
+//
+// The format starts with four magic bytes and a version number. Unsigned
+// integers, including nodal indices, are stored as variable-length integers,
+// and floating-point numbers are stored as 64-bit little-endian values.
+func (self *Model) MarshalBinary() ([]byte, error) {
+	surrogate := self.Surrogate
+	if err := check(surrogate); err != nil {
+		return nil, err
+	}
+	grid, err := describeGrid(self.Grid)
+	if err != nil {
+		return nil, err
+	}
+	basis, err := describeBasis(self.Basis)
+	if err != nil {
+		return nil, err
+	}
+
+	writer := &writer{}
+	writer.Write(magic[:])
+	writer.putUint(Version)
+	writer.putDescriptor(grid)
+	writer.putDescriptor(basis)
+	writer.putUint(uint64(surrogate.Inputs))
+	writer.putUint(uint64(surrogate.Outputs))
+	writer.putUint(uint64(surrogate.Nodes))
+	for _, index := range surrogate.Indices {
+		writer.putUint(index)
+	}
+	writer.putFloats(surrogate.Surpluses)
+	writer.putFloats(surrogate.Integral)
+	if domain := surrogate.Domain; domain != nil {
+		writer.putUint(1)
+		writer.putFloats(domain.Lower)
+		writer.putFloats(domain.Upper)
+	} else {
+		writer.putUint(0)
+	}
+	writer.putUint(uint64(len(surrogate.History)))
+	for _, iteration := range surrogate.History {
+		writer.putUint(uint64(iteration.Nodes))
+		writer.putUint(uint64(iteration.Total))
+		writer.putUint(uint64(iteration.Active))
+		writer.putFloats(iteration.Maximum)
+		writer.putFloats(iteration.Mean)
+		writer.putFloats(iteration.Integral)
+	}
+
+	return writer.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a model encoded using the binary format.
+func (self *Model) UnmarshalBinary(data []byte) (err error) {
+	reader := &reader{Reader: bytes.NewReader(data)}
+
+	var prefix [4]byte
+	if _, err = io.ReadFull(reader, prefix[:]); err != nil || prefix != magic {
+		return errors.New("the data are not a model")
+	}
+	version := reader.getUint()
+	if reader.err == nil && (version == 0 || version > Version) {
+		return fmt.Errorf("the version %d is not supported", version)
+	}
+
+	gridDescriptor := reader.getDescriptor()
+	basisDescriptor := reader.getDescriptor()
+
+	ni := uint(reader.getUint())
+	no := uint(reader.getUint())
+	nn := uint(reader.getUint())
+	if reader.err != nil {
+		return reader.err
+	}
+	size := uint64(len(data))
+	if ni == 0 || no == 0 || uint64(nn) > size/uint64(ni) || uint64(nn) > size/uint64(8*no) {
+		return errors.New("the data are malformed")
+	}
+
+	surrogate := algorithm.NewSurrogate(ni, no)
+	surrogate.Nodes = nn
+	surrogate.Indices = make([]uint64, nn*ni)
+	for i := range surrogate.Indices {
+		surrogate.Indices[i] = reader.getUint()
+	}
+	surrogate.Surpluses = reader.getFloats(nn * no)
+	surrogate.Integral = reader.getFloats(no)
+	if flag := reader.getUint(); flag == 1 {
+		surrogate.Domain = &algorithm.Domain{
+			Lower: reader.getFloats(ni),
+			Upper: reader.getFloats(ni),
+		}
+	} else if flag != 0 {
+		return errors.New("the data are malformed")
+	}
+	if version >= 2 {
+		nh := reader.getUint()
+		if reader.err == nil && nh > size {
+			return errors.New("the data are malformed")
+		}
+		for i := uint64(0); i < nh && reader.err == nil; i++ {
+			surrogate.History = append(surrogate.History, algorithm.Iteration{
+				Nodes:  uint(reader.getUint()),
+				Total:  uint(reader.getUint()),
+				Active: uint(reader.getUint()),
+
+				Maximum:  reader.getFloats(no),
+				Mean:     reader.getFloats(no),
+				Integral: reader.getFloats(no),
+			})
+		}
+	}
+	if reader.err != nil {
+		return reader.err
+	}
+	if reader.Len() > 0 {
+		return errors.New("the data are malformed")
+	}
+	if err = check(surrogate); err != nil {
+		return err
+	}
+
+	grid, err := createGrid(gridDescriptor, ni)
+	if err != nil {
+		return err
+	}
+	basis, err := createBasis(basisDescriptor, ni)
+	if err != nil {
+		return err
+	}
+
+	self.Surrogate, self.Grid, self.Basis = surrogate, grid, basis
+
+	return nil
+}
+
+type writer struct {
+	bytes.Buffer
+	buffer [binary.MaxVarintLen64]byte
+}
+
+func (self *writer) putDescriptor(descriptor descriptor) {
+	self.putUint(uint64(len(descriptor.Name)))
+	self.WriteString(descriptor.Name)
+	self.putUint(uint64(descriptor.Power))
+}
+
+func (self *writer) putFloats(values []float64) {
+	for _, value := range values {
+		binary.LittleEndian.PutUint64(self.buffer[:], math.Float64bits(value))
+		self.Write(self.buffer[:8])
+	}
+}
+
+func (self *writer) putUint(value uint64) {
+	self.Write(self.buffer[:binary.PutUvarint(self.buffer[:], value)])
+}
+
+type reader struct {
+	*bytes.Reader
+	buffer [8]byte
+	err    error
+}
+
+func (self *reader) getDescriptor() (descriptor descriptor) {
+	size := self.getUint()
+	if self.err != nil || size > uint64(self.Len()) {
+		self.fail(io.ErrUnexpectedEOF)
+		return
+	}
+	name := make([]byte, size)
+	if _, err := io.ReadFull(self, name); err != nil {
+		self.fail(err)
+		return
+	}
+	descriptor.Name = string(name)
+	descriptor.Power = uint(self.getUint())
+	return
+}
+
+func (self *reader) getFloats(count uint) []float64 {
+	values := make([]float64, count)
+	for i := range values {
+		if self.err != nil {
+			break
+		}
+		if _, err := io.ReadFull(self, self.buffer[:]); err != nil {
+			self.fail(err)
+			break
+		}
+		values[i] = math.Float64frombits(binary.LittleEndian.Uint64(self.buffer[:]))
+	}
+	return values
+}
+
+func (self *reader) getUint() uint64 {
+	if self.err != nil {
+		return 0
+	}
+	value, err := binary.ReadUvarint(self)
+	if err != nil {
+		self.fail(err)
+	}
+	return value
+}
+
+func (self *reader) fail(err error) {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if self.err == nil {
+		self.err = err
+	}
+}
@@ -0,0 +1,96 @@
+package codec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ready-steady/adapt/algorithm"
+)
+
+type document struct {
+	Version   uint                 `json:"version"`
+	Grid      descriptor           `json:"grid"`
+	Basis     descriptor           `json:"basis"`
+	Surrogate *algorithm.Surrogate `json:"surrogate"`
+}
+
+// MarshalJSON encodes a model using JSON. Since JSON has no representation of
+// infinities and NaNs, models containing them are rejected; the binary format
+// can be used instead.
+func (self *Model) MarshalJSON() ([]byte, error) {
+	if err := check(self.Surrogate); err != nil {
+		return nil, err
+	}
+	if !finite(self.Surrogate) {
+		return nil, errors.New("the surrogate has non-finite values, which JSON does not support")
+	}
+	grid, err := describeGrid(self.Grid)
+	if err != nil {
+		return nil, err
+	}
+	basis, err := describeBasis(self.Basis)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&document{
+		Version:   Version,
+		Grid:      grid,
+		Basis:     basis,
+		Surrogate: self.Surrogate,
+	})
+}
+
+// UnmarshalJSON decodes a model encoded using JSON.
+func (self *Model) UnmarshalJSON(data []byte) error {
+	document := &document{}
+	if err := json.Unmarshal(data, document); err != nil {
+		return err
+	}
+	if document.Version == 0 || document.Version > Version {
+		return fmt.Errorf("the version %d is not supported", document.Version)
+	}
+
+	surrogate := document.Surrogate
+	if surrogate == nil {
+		return fmt.Errorf("the surrogate is missing")
+	}
+	if surrogate.Indices == nil {
+		surrogate.Indices = make([]uint64, 0)
+	}
+	if surrogate.Surpluses == nil {
+		surrogate.Surpluses = make([]float64, 0)
+	}
+	if err := check(surrogate); err != nil {
+		return err
+	}
+
+	grid, err := createGrid(document.Grid, surrogate.Inputs)
+	if err != nil {
+		return err
+	}
+	basis, err := createBasis(document.Basis, surrogate.Inputs)
+	if err != nil {
+		return err
+	}
+
+	self.Surrogate, self.Grid, self.Basis = surrogate, grid, basis
+
+	return nil
+}
+
+func finite(surrogate *algorithm.Surrogate) bool {
+	values := [][]float64{surrogate.Surpluses, surrogate.Integral}
+	for _, iteration := range surrogate.History {
+		values = append(values, iteration.Maximum, iteration.Mean, iteration.Integral)
+	}
+	for _, values := range values {
+		for _, value := range values {
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				return false
+			}
+		}
+	}
+	return true
+}
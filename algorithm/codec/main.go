@@ -0,0 +1,143 @@
+// Package codec provides serialization of interpolants.
+//
+// An interpolant is stored together with the identity and parameters of the
+// grid and basis that it is constructed with so that it can be evaluated after
+// loading without any extra bookkeeping. Two formats are supported: a compact
+// versioned binary format and JSON.
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ready-steady/adapt/algorithm"
+	"github.com/ready-steady/adapt/basis"
+	"github.com/ready-steady/adapt/basis/lagrange"
+	"github.com/ready-steady/adapt/basis/polynomial"
+	"github.com/ready-steady/adapt/grid"
+	"github.com/ready-steady/adapt/grid/chebyshev"
+	"github.com/ready-steady/adapt/grid/equidistant"
+)
+
+const (
+	// Version is the current version of the formats. Version 2 adds the
+	// convergence history; data of version 1 can still be decoded.
+	Version = 2
+)
+
+// Model is an interpolant together with its grid and basis.
+type Model struct {
+	Surrogate *algorithm.Surrogate
+	Grid      Grid
+	Basis     Basis
+}
+
+// Basis is an interpolation basis.
+type Basis interface {
+	basis.Computer
+	basis.Integrator
+}
+
+// Grid is an interpolation grid.
+type Grid interface {
+	grid.Computer
+}
+
+// descriptor identifies a grid or a basis.
+type descriptor struct {
+	Name  string `json:"name"`
+	Power uint   `json:"power,omitempty"`
+}
+
+var (
+	errUnknown = errors.New("the grid or basis is not supported")
+)
+
+func describeGrid(grid Grid) (descriptor, error) {
+	switch grid.(type) {
+	case *equidistant.Closed:
+		return descriptor{Name: "equidistant.Closed"}, nil
+	case *equidistant.Open:
+		return descriptor{Name: "equidistant.Open"}, nil
+	case *chebyshev.Closed:
+		return descriptor{Name: "chebyshev.Closed"}, nil
+	default:
+		return descriptor{}, errUnknown
+	}
+}
+
+func describeBasis(basis Basis) (descriptor, error) {
+	switch basis := basis.(type) {
+	case *polynomial.Closed:
+		return descriptor{Name: "polynomial.Closed", Power: basis.Power()}, nil
+	case *polynomial.Open:
+		return descriptor{Name: "polynomial.Open", Power: basis.Power()}, nil
+	case *lagrange.Closed:
+		return descriptor{Name: "lagrange.Closed"}, nil
+	default:
+		return descriptor{}, errUnknown
+	}
+}
+
+func createGrid(descriptor descriptor, ni uint) (Grid, error) {
+	switch descriptor.Name {
+	case "equidistant.Closed":
+		return equidistant.NewClosed(ni), nil
+	case "equidistant.Open":
+		return equidistant.NewOpen(ni), nil
+	case "chebyshev.Closed":
+		return chebyshev.NewClosed(ni), nil
+	default:
+		return nil, fmt.Errorf("the grid %q is unknown", descriptor.Name)
+	}
+}
+
+func createBasis(descriptor descriptor, ni uint) (Basis, error) {
+	switch descriptor.Name {
+	case "polynomial.Closed":
+		return polynomial.NewClosed(ni, descriptor.Power), nil
+	case "polynomial.Open":
+		return polynomial.NewOpen(ni, descriptor.Power), nil
+	case "lagrange.Closed":
+		return lagrange.NewClosed(ni), nil
+	default:
+		return nil, fmt.Errorf("the basis %q is unknown", descriptor.Name)
+	}
+}
+
+func check(surrogate *algorithm.Surrogate) error {
+	ni, no, nn := surrogate.Inputs, surrogate.Outputs, surrogate.Nodes
+	if ni == 0 || no == 0 {
+		return errors.New("the numbers of inputs and outputs should be positive")
+	}
+	if uint(len(surrogate.Indices)) != nn*ni {
+		return errors.New("the number of indices is inconsistent")
+	}
+	if uint(len(surrogate.Surpluses)) != nn*no {
+		return errors.New("the number of surpluses is inconsistent")
+	}
+	if uint(len(surrogate.Integral)) != no {
+		return errors.New("the size of the integral is inconsistent")
+	}
+	if domain := surrogate.Domain; domain != nil {
+		if uint(len(domain.Lower)) != ni || uint(len(domain.Upper)) != ni {
+			return errors.New("the size of the domain is inconsistent")
+		}
+		for i := uint(0); i < ni; i++ {
+			if !(domain.Lower[i] < domain.Upper[i]) || math.IsInf(domain.Lower[i], 0) ||
+				math.IsInf(domain.Upper[i], 0) {
+
+				return errors.New("the bounds of the domain are invalid")
+			}
+		}
+	}
+	for _, iteration := range surrogate.History {
+		if uint(len(iteration.Maximum)) != no || uint(len(iteration.Mean)) != no ||
+			uint(len(iteration.Integral)) != no {
+
+			return errors.New("the size of the history is inconsistent")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package codec
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/ready-steady/adapt/algorithm/global"
+	"github.com/ready-steady/adapt/algorithm/local"
+	"github.com/ready-steady/adapt/basis/lagrange"
+	"github.com/ready-steady/adapt/basis/polynomial"
+	"github.com/ready-steady/adapt/grid/chebyshev"
+	"github.com/ready-steady/adapt/grid/equidistant"
+	"github.com/ready-steady/assert"
+
+	interpolation "github.com/ready-steady/adapt/algorithm"
+)
+
+func TestBinary(t *testing.T) {
+	for _, model := range prepare() {
+		data, err := model.MarshalBinary()
+		assert.Equal(err, nil, t)
+
+		loaded := &Model{}
+		assert.Equal(loaded.UnmarshalBinary(data), nil, t)
+		compare(model, loaded, t)
+
+		assert.Equal(loaded.UnmarshalBinary(data[:len(data)-1]) != nil, true, t)
+		assert.Equal(loaded.UnmarshalBinary(data[1:]) != nil, true, t)
+	}
+}
+
+func TestBinaryVersion1(t *testing.T) {
+	model := prepare()[0]
+	assert.Equal(model.Surrogate.History == nil, true, t)
+
+	data, err := model.MarshalBinary()
+	assert.Equal(err, nil, t)
+	assert.Equal(data[4], byte(Version), t)
+	assert.Equal(data[len(data)-1], byte(0), t)
+
+	data[4], data = 1, data[:len(data)-1]
+
+	loaded := &Model{}
+	assert.Equal(loaded.UnmarshalBinary(data), nil, t)
+	compare(model, loaded, t)
+}
+
+func TestJSON(t *testing.T) {
+	for _, model := range prepare() {
+		data, err := model.MarshalJSON()
+		assert.Equal(err, nil, t)
+
+		loaded := &Model{}
+		assert.Equal(loaded.UnmarshalJSON(data), nil, t)
+		compare(model, loaded, t)
+	}
+}
+
+func compare(one, two *Model, t *testing.T) {
+	assert.Equal(two.Surrogate, one.Surrogate, t)
+	assert.Equal(two.Grid, one.Grid, t)
+
+	points := []float64{0.1, 0.2, 0.5, 0.5, 0.9, 0.3}
+	if domain := one.Surrogate.Domain; domain != nil {
+		points = domain.Forward(points)
+	}
+
+	ni, no := one.Surrogate.Inputs, one.Surrogate.Outputs
+	assert.Equal(local.New(ni, no, two.Grid, two.Basis).Evaluate(two.Surrogate, points),
+		local.New(ni, no, one.Grid, one.Basis).Evaluate(one.Surrogate, points), t)
+}
+
+func prepare() []*Model {
+	const (
+		ni = 2
+		no = 2
+	)
+
+	target := func(x, y []float64) {
+		y[0] = math.Sin(x[0]) * math.Cos(x[1])
+		y[1] = x[0] * x[1]
+	}
+
+	domain := interpolation.NewDomain([]float64{-1.0, 0.0}, []float64{1.0, 2.0})
+
+	models := []*Model{}
+
+	{
+		grid, basis := equidistant.NewClosed(ni), polynomial.NewClosed(ni, 2)
+		algorithm := local.New(ni, no, grid, basis)
+		algorithm.Restrict(domain)
+		strategy := local.NewStrategy(ni, no, grid, 1, 5, 1e-3)
+		models = append(models, &Model{algorithm.Compute(target, strategy), grid, basis})
+	}
+
+	{
+		grid, basis := equidistant.NewOpen(ni), polynomial.NewOpen(ni, 3)
+		algorithm := local.New(ni, no, grid, basis)
+		algorithm.Record()
+		strategy := local.NewStrategy(ni, no, grid, 1, 5, 1e-3)
+		models = append(models, &Model{algorithm.Compute(target, strategy), grid, basis})
+	}
+
+	{
+		grid, basis := chebyshev.NewClosed(ni), lagrange.NewClosed(ni)
+		algorithm := global.New(ni, no, grid, basis)
+		algorithm.Record()
+		strategy := global.NewStrategy(ni, no, grid, 1, 5, 1e-6, 1e-3)
+		models = append(models, &Model{algorithm.Compute(target, strategy), grid, basis})
+	}
+
+	return models
+}
+
+func TestBinaryMalformed(t *testing.T) {
+	model := prepare()[0]
+	ni := model.Surrogate.Inputs
+	assert.Equal(model.Surrogate.Domain != nil, true, t)
+
+	data, err := model.MarshalBinary()
+	assert.Equal(err, nil, t)
+
+	loaded := &Model{}
+	assert.Equal(loaded.UnmarshalBinary(append(data, 0)) != nil, true, t)
+
+	// The data end with the domain flag, the bounds, and an empty history.
+	flag := len(data) - 1 - int(2*ni*8) - 1
+	assert.Equal(data[flag], byte(1), t)
+
+	corrupted := append([]byte(nil), data...)
+	corrupted[flag] = 2
+	assert.Equal(loaded.UnmarshalBinary(corrupted) != nil, true, t)
+
+	corrupted = append([]byte(nil), data...)
+	binary.LittleEndian.PutUint64(corrupted[flag+1:], math.Float64bits(5.0))
+	assert.Equal(loaded.UnmarshalBinary(corrupted) != nil, true, t)
+}
+
+func TestNonFinite(t *testing.T) {
+	model := prepare()[0]
+	model.Surrogate.Surpluses[0] = math.NaN()
+
+	_, err := model.MarshalJSON()
+	assert.Equal(err != nil, true, t)
+
+	data, err := model.MarshalBinary()
+	assert.Equal(err, nil, t)
+
+	loaded := &Model{}
+	assert.Equal(loaded.UnmarshalBinary(data), nil, t)
+	assert.Equal(math.IsNaN(loaded.Surrogate.Surpluses[0]), true, t)
+}
@@ -0,0 +1,243 @@
+// Package density provides density estimation from samples on sparse grids.
+//
+// Given a set of samples, the surpluses of a sparse-grid function are fitted
+// by the regularized L2 projection of the empirical distribution of the
+// samples, that is, by minimizing the integral of the squared function minus
+// twice its mean over the samples plus a multiple of the squared norm of the
+// surpluses. The corresponding linear system, whose matrix is composed of the
+// integrals of the products of the basis functions, is solved by the conjugate
+// gradient method. The grid can also be refined adaptively. The result is
+// normalized to integrate to one and is an ordinary interpolant, which can be
+// evaluated, for instance, using local.Algorithm.
+package density
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ready-steady/adapt/algorithm"
+	"github.com/ready-steady/adapt/algorithm/hierarchy"
+	"github.com/ready-steady/adapt/algorithm/internal"
+	"github.com/ready-steady/adapt/basis"
+	"github.com/ready-steady/adapt/grid"
+)
+
+const (
+	// Iterations is the default maximum number of iterations of the conjugate
+	// gradient method.
+	Iterations = 1000
+	// Tolerance is the default relative tolerance of the conjugate gradient
+	// method.
+	Tolerance = 1e-10
+)
+
+// Estimator is a sparse-grid density estimator.
+type Estimator struct {
+	ni uint
+
+	grid   Grid
+	basis  Basis
+	domain *algorithm.Domain
+
+	lmin     uint
+	lmax     uint
+	limit    *internal.Limit
+	λ        float64
+	εs       float64
+	nodes    uint
+	truncate bool
+
+	iterations uint
+	εc         float64
+}
+
+// Basis is an interpolation basis.
+type Basis interface {
+	basis.Computer
+	basis.Integrator
+	basis.IntegratorProduct
+	basis.Supporter
+}
+
+// Grid is an interpolation grid.
+type Grid interface {
+	grid.Computer
+	grid.Indexer
+	grid.Parenter
+	grid.Refiner
+}
+
+// New creates a density estimator. The regularization parameter is the weight
+// of the squared norm of the surpluses, and the score error is the probability
+// mass attributed to a node above which the node is refined; see Compute.
+func New(inputs uint, grid Grid, basis Basis, minLevel, maxLevel uint,
+	regularization, scoreError float64) *Estimator {
+
+	return &Estimator{
+		ni: inputs,
+
+		grid:  grid,
+		basis: basis,
+
+		lmin:  minLevel,
+		lmax:  maxLevel,
+		limit: internal.NewLimit(inputs),
+		λ:     regularization,
+		εs:    scoreError,
+
+		iterations: Iterations,
+		εc:         Tolerance,
+	}
+}
+
+// Restrict sets the domain of the samples, which is the unit hypercube by
+// default. The samples passed to Fit and Compute are then expressed with
+// respect to the domain, and so is the density.
+func (self *Estimator) Restrict(domain *algorithm.Domain) {
+	self.domain = domain
+}
+
+// Limit sets the maximum number of nodes. A zero value means no limit. When
+// the number of nodes is limited, the nodes with the highest scores are refined
+// first, and the refinements that do not fit are skipped.
+func (self *Estimator) Limit(nodes uint) {
+	self.nodes = nodes
+}
+
+// Iterate sets the maximum number of iterations and the relative tolerance of
+// the conjugate gradient method.
+func (self *Estimator) Iterate(iterations uint, tolerance float64) {
+	self.iterations, self.εc = iterations, tolerance
+}
+
+// Truncate enables the clamping of the values of the density at the nodes at
+// zero before normalization, which makes the density non-negative at the
+// nodes. Between the nodes, the density can still be negative, except for
+// piecewise-linear bases in one dimension.
+func (self *Estimator) Truncate() {
+	self.truncate = true
+}
+
+// Fit constructs a density with a given set of indices from a set of samples.
+func (self *Estimator) Fit(indices []uint64, samples []float64) *algorithm.Surrogate {
+	samples = self.prepare(samples)
+	surpluses := self.solve(newSystem(self.basis, samples, self.ni), indices, nil)
+	return self.assemble(indices, surpluses)
+}
+
+// Compute constructs a density from a set of samples by refining the grid
+// adaptively. The process starts with the root node and, after each fit,
+// refines the nodes whose scores exceed the score error given at creation,
+// where the score of a node is the absolute value of its surplus multiplied by
+// the integral of its basis function. The process stops when no new nodes are
+// produced.
+func (self *Estimator) Compute(samples []float64) *algorithm.Surrogate {
+	ni := self.ni
+	samples = self.prepare(samples)
+
+	system := newSystem(self.basis, samples, ni)
+	unique := internal.NewUnique(ni)
+	indices := unique.Distil(self.grid.Index(make([]uint64, ni)))
+	surpluses := []float64(nil)
+	for {
+		surpluses = self.solve(system, indices, surpluses)
+		scores := self.score(indices, surpluses)
+		fresh := self.refine(unique, indices, scores)
+		if len(fresh) == 0 {
+			break
+		}
+		indices = append(indices, fresh...)
+	}
+
+	return self.assemble(indices, surpluses)
+}
+
+func (self *Estimator) assemble(indices []uint64, surpluses []float64) *algorithm.Surrogate {
+	ni := self.ni
+
+	if self.truncate {
+		surrogate := algorithm.NewSurrogate(ni, 1)
+		surrogate.Push(indices, surpluses, internal.Measure(self.basis, indices, ni))
+		values := hierarchy.Dehierarchize(self.grid, self.basis, surrogate)
+		for i := range values {
+			values[i] = math.Max(values[i], 0.0)
+		}
+		surpluses = hierarchy.Hierarchize(self.grid, self.basis, indices, values, ni, 1).Surpluses
+	}
+
+	volumes := internal.Measure(self.basis, indices, ni)
+	mass := 0.0
+	for i := range surpluses {
+		mass += surpluses[i] * volumes[i]
+	}
+	if mass <= 0.0 {
+		panic("the density cannot be normalized")
+	}
+	if self.domain != nil {
+		internal.Scale(volumes, self.domain.Volume())
+		mass *= self.domain.Volume()
+	}
+	internal.Scale(surpluses, 1.0/mass)
+
+	surrogate := algorithm.NewSurrogate(ni, 1)
+	surrogate.Domain = self.domain
+	surrogate.Push(indices, surpluses, volumes)
+	return surrogate
+}
+
+func (self *Estimator) prepare(samples []float64) []float64 {
+	ni := self.ni
+	ns := uint(len(samples)) / ni
+	if uint(len(samples)) != ns*ni {
+		panic("the number of coordinates does not match the number of inputs")
+	}
+	if ns == 0 {
+		panic("there should be at least one sample")
+	}
+	if self.domain != nil {
+		samples = self.domain.Backward(samples)
+	}
+	return samples
+}
+
+func (self *Estimator) refine(unique *internal.Unique, indices []uint64,
+	scores []float64) []uint64 {
+
+	ni := self.ni
+	nn := uint(len(scores))
+
+	order := []uint{}
+	for i := uint(0); i < nn; i++ {
+		index := indices[i*ni : (i+1)*ni]
+		if self.limit.Below(index, self.lmin) ||
+			(scores[i] > self.εs && !self.limit.Reached(index, self.lmax)) {
+
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+
+	fresh := []uint64{}
+	for _, i := range order {
+		children := self.limit.Filter(self.grid.Refine(indices[i*ni:(i+1)*ni]), self.lmax)
+		if self.nodes > 0 {
+			count := unique.Count(children)
+			if nn+uint(len(fresh))/ni+count > self.nodes {
+				continue
+			}
+		}
+		fresh = append(fresh, unique.Distil(children)...)
+	}
+	return fresh
+}
+
+func (self *Estimator) score(indices []uint64, surpluses []float64) []float64 {
+	volumes := internal.Measure(self.basis, indices, self.ni)
+	scores := make([]float64, len(volumes))
+	for i := range scores {
+		scores[i] = math.Abs(surpluses[i] * volumes[i])
+	}
+	return scores
+}
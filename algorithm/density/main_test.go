@@ -0,0 +1,74 @@
+package density
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/ready-steady/adapt/algorithm"
+	"github.com/ready-steady/adapt/algorithm/local"
+	"github.com/ready-steady/adapt/basis/polynomial"
+	"github.com/ready-steady/adapt/grid/equidistant"
+	"github.com/ready-steady/assert"
+)
+
+func TestFit(t *testing.T) {
+	const (
+		ni = 2
+		ns = 20000
+	)
+
+	grid, basis := equidistant.NewClosed(ni), polynomial.NewClosed(ni, 1)
+	indices := grid.Index([]uint64{1, 1})
+	indices = append(indices, grid.Index([]uint64{0, 0})...)
+	indices = append(indices, grid.Index([]uint64{0, 1})...)
+	indices = append(indices, grid.Index([]uint64{1, 0})...)
+
+	estimator := New(ni, grid, basis, 0, 2, 0.0, 0.0)
+	surrogate := estimator.Fit(indices, sample(ns, ni))
+	assert.Close(surrogate.Integral, []float64{1.0}, 1e-12, t)
+
+	points := []float64{0.5, 0.5, 0.25, 0.5, 0.25, 0.75}
+	values := local.New(ni, 1, grid, basis).Evaluate(surrogate, points)
+	assert.Close(values, []float64{4.0, 2.0, 1.0}, 0.1, t)
+}
+
+func TestCompute(t *testing.T) {
+	const (
+		ni = 2
+		ns = 20000
+	)
+
+	domain := algorithm.NewDomain([]float64{-1.0, 0.0}, []float64{1.0, 4.0})
+	samples := domain.Forward(sample(ns, ni))
+
+	grid, basis := equidistant.NewClosed(ni), polynomial.NewClosed(ni, 1)
+	estimator := New(ni, grid, basis, 1, 4, 1e-4, 1e-3)
+	estimator.Restrict(domain)
+	estimator.Truncate()
+	surrogate := estimator.Compute(samples)
+	assert.Equal(surrogate.Domain, domain, t)
+	assert.Close(surrogate.Integral, []float64{1.0}, 1e-12, t)
+
+	algorithm := local.New(ni, 1, grid, basis)
+	values := algorithm.Evaluate(surrogate, domain.Forward(grid.Compute(surrogate.Indices)))
+	for _, value := range values {
+		assert.Equal(value >= -1e-12, true, t)
+	}
+	values = algorithm.Evaluate(surrogate, []float64{0.0, 2.0, -0.5, 1.0})
+	assert.Close(values, []float64{0.5, 0.125}, 0.05, t)
+
+	estimator.Limit(20)
+	assert.Equal(estimator.Compute(samples).Nodes <= 20, true, t)
+}
+
+// sample draws samples from the product of triangular distributions on the unit
+// interval with the mode at one half, whose density is 4 at the center of the
+// unit hypercube.
+func sample(ns, ni uint) []float64 {
+	generator := rand.New(rand.NewSource(0))
+	samples := make([]float64, ns*ni)
+	for i := range samples {
+		samples[i] = (generator.Float64() + generator.Float64()) / 2.0
+	}
+	return samples
+}
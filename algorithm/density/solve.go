@@ -0,0 +1,116 @@
+package density
+
+import (
+	"github.com/ready-steady/adapt/algorithm/internal"
+
+	rinternal "github.com/ready-steady/adapt/internal"
+)
+
+// system is the linear system
+//
+//	(M + λ·I) α = Bᵀ1 / ns
+//
+// where M is the matrix of the integrals of the products of the basis
+// functions, and B is the matrix of the basis functions evaluated at the
+// samples. Since the products vanish unless the supports of the basis
+// functions overlap, M is stored as a sparse upper triangle. When indices are
+// appended, only the entries involving the new ones are computed.
+type system struct {
+	ni uint
+
+	basis   Basis
+	samples []float64
+
+	neighbors [][]uint
+	products  [][]float64
+	b         []float64
+}
+
+func newSystem(basis Basis, samples []float64, ni uint) *system {
+	return &system{
+		ni: ni,
+
+		basis:   basis,
+		samples: samples,
+	}
+}
+
+// extend takes into account the indices appended since the previous call.
+func (self *system) extend(indices []uint64) {
+	ni := self.ni
+	nn := uint(len(indices)) / ni
+	nk := uint(len(self.b))
+	ns := uint(len(self.samples)) / ni
+
+	self.neighbors = rinternal.Overlap(self.basis, indices, ni)
+	self.products = append(self.products, make([][]float64, nn-nk)...)
+	for i := uint(0); i < nn; i++ {
+		index := indices[i*ni : (i+1)*ni]
+		for _, j := range self.neighbors[i][len(self.products[i]):] {
+			self.products[i] = append(self.products[i],
+				self.basis.IntegrateProduct(index, indices[j*ni:(j+1)*ni]))
+		}
+	}
+
+	weights := make([]float64, ns)
+	internal.Set(weights, 1.0/float64(ns))
+	self.b = append(self.b, internal.Project(self.basis, indices[nk*ni:], weights,
+		self.samples, ni, 1)...)
+}
+
+// multiply computes the product of M and a vector.
+func (self *system) multiply(x []float64) []float64 {
+	y := make([]float64, len(x))
+	for i, neighbors := range self.neighbors {
+		for k, j := range neighbors {
+			product := self.products[i][k]
+			y[i] += product * x[j]
+			if j != uint(i) {
+				y[j] += product * x[i]
+			}
+		}
+	}
+	return y
+}
+
+// diagonal returns the diagonal of M.
+func (self *system) diagonal() []float64 {
+	diagonal := make([]float64, len(self.products))
+	for i := range diagonal {
+		// The first neighbor of a basis function is the function itself.
+		diagonal[i] = self.products[i][0]
+	}
+	return diagonal
+}
+
+// solve fits the surpluses of a set of indices, which extend the ones of the
+// previous call, if any, by solving the system with the conjugate gradient
+// method preconditioned by the diagonal of the system matrix. The initial
+// surpluses, if any, correspond to a prefix of the indices.
+func (self *Estimator) solve(system *system, indices []uint64, initial []float64) []float64 {
+	nn := uint(len(indices)) / self.ni
+	system.extend(indices)
+
+	multiply := func(x []float64) []float64 {
+		y := system.multiply(x)
+		for i := range y {
+			y[i] += self.λ * x[i]
+		}
+		return y
+	}
+	diagonal := system.diagonal()
+	precondition := func(x []float64) []float64 {
+		y := make([]float64, nn)
+		for i := uint(0); i < nn; i++ {
+			if d := diagonal[i] + self.λ; d > 0.0 {
+				y[i] = x[i] / d
+			}
+		}
+		return y
+	}
+
+	x := make([]float64, nn)
+	copy(x, initial)
+
+	return internal.Solve(multiply, precondition, system.b, x, nn, 1, self.iterations, self.εc)
+}
@@ -0,0 +1,64 @@
+package algorithm
+
+// Domain is a rectangular domain.
+type Domain struct {
+	Lower []float64 // Lower bounds
+	Upper []float64 // Upper bounds
+}
+
+// NewDomain creates a domain. The bounds are copied.
+func NewDomain(lower, upper []float64) *Domain {
+	if len(lower) != len(upper) {
+		panic("the bounds have different dimensions")
+	}
+	for i := range lower {
+		if lower[i] >= upper[i] {
+			panic("the lower bounds should be less than the upper ones")
+		}
+	}
+	return &Domain{
+		Lower: append([]float64(nil), lower...),
+		Upper: append([]float64(nil), upper...),
+	}
+}
+
+// Forward maps points from the unit hypercube to the domain.
+func (self *Domain) Forward(points []float64) []float64 {
+	ni := len(self.Lower)
+	result := make([]float64, len(points))
+	for i := range points {
+		j := i % ni
+		result[i] = self.Lower[j] + (self.Upper[j]-self.Lower[j])*points[i]
+	}
+	return result
+}
+
+// Backward maps points from the domain to the unit hypercube.
+func (self *Domain) Backward(points []float64) []float64 {
+	ni := len(self.Lower)
+	result := make([]float64, len(points))
+	for i := range points {
+		j := i % ni
+		result[i] = (points[i] - self.Lower[j]) / (self.Upper[j] - self.Lower[j])
+	}
+	return result
+}
+
+// Volume returns the volume of the domain.
+func (self *Domain) Volume() float64 {
+	volume := 1.0
+	for i := range self.Lower {
+		volume *= self.Upper[i] - self.Lower[i]
+	}
+	return volume
+}
+
+// Jacobian returns the diagonal of the Jacobian matrix of Backward, which is
+// used for computing derivatives with respect to the domain.
+func (self *Domain) Jacobian() []float64 {
+	jacobian := make([]float64, len(self.Lower))
+	for i := range jacobian {
+		jacobian[i] = 1.0 / (self.Upper[i] - self.Lower[i])
+	}
+	return jacobian
+}
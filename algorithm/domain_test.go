@@ -0,0 +1,27 @@
+package algorithm
+
+import (
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestNewDomain(t *testing.T) {
+	lower, upper := []float64{1.0, -1.0}, []float64{3.0, 2.0}
+	domain := NewDomain(lower, upper)
+	lower[0], upper[0] = 0.0, 0.0
+	assert.Equal(domain.Lower, []float64{1.0, -1.0}, t)
+	assert.Equal(domain.Upper, []float64{3.0, 2.0}, t)
+}
+
+func TestDomain(t *testing.T) {
+	domain := NewDomain([]float64{1.0, -1.0}, []float64{3.0, 2.0})
+
+	unit := []float64{0.0, 0.0, 0.25, 0.5, 0.95, 0.7, 1.0, 1.0}
+	points := []float64{1.0, -1.0, 1.5, 0.5, 2.9, 1.1, 3.0, 2.0}
+	assert.Close(domain.Forward(unit), points, 1e-14, t)
+	assert.Close(domain.Backward(points), unit, 1e-14, t)
+
+	assert.Equal(domain.Volume(), 6.0, t)
+	assert.Close(domain.Jacobian(), []float64{0.5, 1.0 / 3.0}, 1e-14, t)
+}
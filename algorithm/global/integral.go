@@ -0,0 +1,138 @@
+package global
+
+import (
+	"math"
+
+	"github.com/ready-steady/adapt/algorithm"
+	"github.com/ready-steady/adapt/algorithm/internal"
+)
+
+// IntegralStrategy is a strategy aiming at the integral of the target.
+//
+// Elements are scored by their contributions to the integral, that is, by their
+// surpluses multiplied by the volumes of their basis functions, and the
+// priority of a level index is the sum of the scores of its nodes. The
+// refinement stops when the estimated change of the integral of each output,
+// which is the sum of the absolute contributions of the active level indices,
+// falls below the tolerance given by the absolute error and the relative
+// error with respect to the magnitude of the integral.
+type IntegralStrategy struct {
+	Strategy
+
+	εa []float64
+	εr []float64
+
+	integral []float64
+}
+
+// NewIntegralStrategy creates an integral strategy.
+func NewIntegralStrategy(inputs, outputs uint, guide Guide, minLevel, maxLevel uint,
+	absoluteError, relativeError float64) *IntegralStrategy {
+
+	return &IntegralStrategy{
+		Strategy: *NewStrategy(inputs, outputs, guide, minLevel, maxLevel,
+			absoluteError, relativeError),
+
+		εa: repeat(absoluteError, outputs),
+		εr: repeat(relativeError, outputs),
+
+		integral: make([]float64, outputs),
+	}
+}
+
+// Tolerate sets the absolute and relative errors of each output, which
+// replace the errors given at creation.
+func (self *IntegralStrategy) Tolerate(absolute, relative []float64) {
+	if uint(len(absolute)) != self.no || uint(len(relative)) != self.no {
+		panic("the number of errors should be equal to the number of outputs")
+	}
+	self.εa = append([]float64(nil), absolute...)
+	self.εr = append([]float64(nil), relative...)
+}
+
+func (self *IntegralStrategy) Next(state *algorithm.State,
+	surrogate *algorithm.Surrogate) *algorithm.State {
+
+	return self.next(state, surrogate, self.consume, self.check)
+}
+
+func (self *IntegralStrategy) Resume(state *algorithm.State,
+	surrogate *algorithm.Surrogate) *algorithm.State {
+
+	return self.resume(state, surrogate, self.Next)
+}
+
+func (self *IntegralStrategy) Report(progress *algorithm.Progress) {
+	progress.Active = uint(len(self.active.Positions))
+	progress.Threshold = make([]float64, self.no)
+	for i := range progress.Threshold {
+		progress.Threshold[i] = self.tolerance(uint(i))
+	}
+}
+
+func (self *IntegralStrategy) Score(element *algorithm.Element) float64 {
+	return self.norm.Compute(element.Surplus, self.weights) * element.Volume
+}
+
+func (self *IntegralStrategy) check() bool {
+	no := self.no
+	for i := uint(0); i < no; i++ {
+		change := 0.0
+		for k := range self.active.Positions {
+			change += self.accuracy[k*no+i]
+		}
+		if change > self.tolerance(i) {
+			return false
+		}
+	}
+	return true
+}
+
+func (self *IntegralStrategy) consume(state *algorithm.State) {
+	ni, no := self.ni, self.no
+	np := uint(len(self.priority))
+	na := uint(len(self.accuracy))
+	nn := uint(len(state.Counts))
+
+	self.priority = append(self.priority, make([]float64, nn)...)
+	priority := self.priority[np:]
+
+	self.accuracy = append(self.accuracy, make([]float64, nn*no)...)
+	accuracy := self.accuracy[na:]
+
+	contribution := make([]float64, no)
+	for i, o := uint(0), uint(0); i < nn; i++ {
+		count := state.Counts[i]
+		internal.Set(contribution, 0.0)
+		for j := o; j < o+count; j++ {
+			for l := uint(0); l < no; l++ {
+				contribution[l] += state.Surpluses[j*no+l] * state.Volumes[j]
+			}
+		}
+		for l := uint(0); l < no; l++ {
+			self.integral[l] += contribution[l]
+		}
+		if self.limit.Below(state.Lndices[i*ni:(i+1)*ni], self.lmin) {
+			priority[i] = internal.Infinity
+			internal.Set(accuracy[i*no:(i+1)*no], internal.Infinity)
+		} else if !self.limit.Reached(state.Lndices[i*ni:(i+1)*ni], self.lmax) {
+			for _, score := range state.Scores[o:(o + count)] {
+				priority[i] += score
+			}
+			for l := uint(0); l < no; l++ {
+				accuracy[i*no+l] = math.Abs(contribution[l])
+			}
+		}
+		o += count
+	}
+}
+
+func (self *IntegralStrategy) tolerance(i uint) float64 {
+	return math.Max(self.εa[i], self.εr[i]*math.Abs(self.integral[i]))
+}
+
+func repeat(value float64, times uint) []float64 {
+	values := make([]float64, times)
+	internal.Set(values, value)
+	return values
+}
@@ -1,8 +1,14 @@
 package global
 
 import (
+	"sort"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/ready-steady/adapt/basis/polynomial"
+	"github.com/ready-steady/adapt/grid/equidistant"
+	"github.com/ready-steady/adapt/internal"
 	"github.com/ready-steady/assert"
 
 	interpolation "github.com/ready-steady/adapt/algorithm"
@@ -28,3 +34,334 @@ func TestBranin(t *testing.T) {
 	values := algorithm.Evaluate(surrogate, fixture.points)
 	assert.Close(values, fixture.values, 0.1, t)
 }
+
+func TestProcess(t *testing.T) {
+	fixture := &fixtureBranin
+	algorithm, strategy := prepare(fixture)
+	ni, no := fixture.surrogate.Inputs, fixture.surrogate.Outputs
+
+	process := algorithm.Start(strategy)
+	for !process.Done() {
+		positions, nodes := process.Ask()
+		nn := uint(len(positions))
+		for i := nn; i > 0; i-- {
+			values := make([]float64, no)
+			fixture.target(nodes[(i-1)*ni:i*ni], values)
+			assert.Equal(process.Tell(positions[i-1:i], values), nil, t)
+		}
+	}
+
+	algorithm, strategy = prepare(fixture)
+	assert.Equal(process.Surrogate(), algorithm.Compute(fixture.target, strategy), t)
+}
+
+func TestLimit(t *testing.T) {
+	fixture := &fixtureBranin
+
+	algorithm, strategy := prepare(fixture)
+	strategy.(*Strategy).Limit(100, 0)
+	surrogate := algorithm.Compute(fixture.target, strategy)
+	assert.Equal(surrogate.Nodes <= 100, true, t)
+	assert.Equal(surrogate.Nodes > 90, true, t)
+	assert.Equal(interpolation.Validate(surrogate.Indices, surrogate.Inputs,
+		fixture.grid), true, t)
+
+	algorithm, strategy = prepare(fixture)
+	strategy.(*Strategy).Limit(0, time.Nanosecond)
+	surrogate = algorithm.Compute(fixture.target, strategy)
+	assert.Equal(surrogate.Nodes, uint(1), t)
+}
+
+func TestMonitor(t *testing.T) {
+	fixture := &fixtureBranin
+	algorithm, strategy := prepare(fixture)
+
+	progresses := []*interpolation.Progress{}
+	algorithm.Monitor(observer(func(progress *interpolation.Progress) {
+		progresses = append(progresses, progress)
+	}))
+	surrogate := algorithm.Compute(fixture.target, strategy)
+
+	nn := uint(0)
+	for i, progress := range progresses {
+		nn += progress.Nodes
+		assert.Equal(progress.Iteration, uint(i), t)
+		assert.Equal(progress.Total, nn, t)
+		assert.Equal(len(progress.Threshold), 1, t)
+	}
+	assert.Equal(nn, surrogate.Nodes, t)
+	assert.Equal(progresses[0].Active, uint(2), t)
+}
+
+type observer func(*interpolation.Progress)
+
+func (self observer) Observe(progress *interpolation.Progress) {
+	self(progress)
+}
+
+func TestWorkStrategy(t *testing.T) {
+	const (
+		minLevel      = 1
+		maxLevel      = 10
+		absoluteError = 1e-6
+		relativeError = 1e-3
+	)
+
+	fixture := &fixtureBranin
+	ni, no := fixture.surrogate.Inputs, fixture.surrogate.Outputs
+
+	algorithm, strategy := prepare(fixture)
+	expected := algorithm.Compute(fixture.target, strategy)
+
+	strategy = NewWorkStrategy(ni, no, fixture.grid, minLevel, maxLevel,
+		absoluteError, relativeError, 1.0)
+	surrogate := algorithm.Compute(fixture.target, strategy)
+	assert.Equal(surrogate, expected, t)
+
+	strategy = NewWorkStrategy(ni, no, fixture.grid, minLevel, maxLevel,
+		absoluteError, relativeError, 0.5)
+	surrogate = algorithm.Compute(fixture.target, strategy)
+	assert.Equal(interpolation.Validate(surrogate.Indices, surrogate.Inputs,
+		fixture.grid), true, t)
+
+	values := algorithm.Evaluate(surrogate, fixture.points)
+	assert.Close(values, fixture.values, 0.1, t)
+}
+
+func TestWorkStrategyResume(t *testing.T) {
+	const (
+		minLevel      = 1
+		maxLevel      = 10
+		absoluteError = 1e-6
+		relativeError = 1e-3
+	)
+
+	fixture := &fixtureBranin
+	ni, no := fixture.surrogate.Inputs, fixture.surrogate.Outputs
+
+	algorithm, _ := prepare(fixture)
+	strategy := NewWorkStrategy(ni, no, fixture.grid, minLevel, 3,
+		absoluteError, relativeError, 0.5)
+	coarse := algorithm.Compute(fixture.target, strategy)
+
+	// Keep only the level indices along the axes except for (1, 0), which
+	// leaves the latter empty and active after resumption.
+	partial := interpolation.NewSurrogate(ni, no)
+	for i := uint(0); i < coarse.Nodes; i++ {
+		index := coarse.Indices[i*ni : (i+1)*ni]
+		l0, l1 := internal.LEVEL_MASK&index[0], internal.LEVEL_MASK&index[1]
+		if l0 == 1 && l1 == 0 || l0 > 0 && l1 > 0 {
+			continue
+		}
+		partial.Push(index, coarse.Surpluses[i*no:(i+1)*no], []float64{0.0})
+	}
+	assert.Equal(partial.Nodes < coarse.Nodes, true, t)
+	nodes := fixture.grid.Compute(partial.Indices)
+	values := interpolation.Invoke(fixture.target, nodes, ni, no)
+
+	strategy = NewWorkStrategy(ni, no, fixture.grid, minLevel, maxLevel,
+		absoluteError, relativeError, 0.5)
+	surrogate := algorithm.Refine(fixture.target, strategy, partial, values)
+	assert.Equal(surrogate.Nodes > coarse.Nodes, true, t)
+
+	for k, cost := range strategy.costs {
+		assert.Equal(cost, strategy.count(k), t)
+	}
+}
+
+func TestTolerate(t *testing.T) {
+	const (
+		ni = 2
+	)
+
+	fixture := &fixtureBranin
+	target := func(x, y []float64) {
+		fixture.target(x, y[:1])
+		y[1] = 1e6 * y[0]
+	}
+
+	algorithm, strategy := prepare(fixture)
+	expected := algorithm.Compute(fixture.target, strategy)
+
+	algorithm = New(ni, 2, fixture.grid, fixture.basis)
+	strategy = NewStrategy(ni, 2, fixture.grid, 1, 10, 0.0, 0.0)
+	strategy.(*Strategy).Weigh([]float64{1.0, 0.0})
+	strategy.(*Strategy).Tolerate([]float64{1e-6, 1e0}, []float64{1e-3, 1e-3})
+	surrogate := algorithm.Compute(target, strategy)
+	assert.Equal(surrogate.Indices, expected.Indices, t)
+}
+
+func TestBoundLevels(t *testing.T) {
+	fixture := &fixtureBranin
+
+	algorithm, strategy := prepare(fixture)
+	strategy.(*Strategy).BoundLevels([]uint{0, 0}, []uint{2, 10})
+	surrogate := algorithm.Compute(fixture.target, strategy)
+	assert.Equal(interpolation.Validate(surrogate.Indices, surrogate.Inputs,
+		fixture.grid), true, t)
+	assert.Equal(maxLevels(surrogate)[0], uint64(2), t)
+
+	algorithm, strategy = prepare(fixture)
+	strategy.(*Strategy).WeighLevels([]float64{1.0, 4.0})
+	surrogate = algorithm.Compute(fixture.target, strategy)
+	assert.Equal(interpolation.Validate(surrogate.Indices, surrogate.Inputs,
+		fixture.grid), true, t)
+	levels := maxLevels(surrogate)
+	assert.Equal(levels[0] > levels[1], true, t)
+	assert.Equal(levels[1] <= 3, true, t)
+
+	lmax := uint64(strategy.(*Strategy).lmax)
+	for i := uint(0); i < surrogate.Nodes; i++ {
+		index := surrogate.Indices[i*2 : (i+1)*2]
+		norm := internal.LEVEL_MASK&index[0] + 4*(internal.LEVEL_MASK&index[1])
+		assert.Equal(norm <= lmax, true, t)
+	}
+}
+
+func TestBoundLevelsBelow(t *testing.T) {
+	const (
+		ni = 2
+		no = 1
+	)
+
+	grid, basis := equidistant.NewClosed(ni), polynomial.NewClosed(ni, 1)
+	algorithm := New(ni, no, grid, basis)
+	target := func(x, y []float64) {
+		y[0] = x[0]
+	}
+
+	strategy := NewStrategy(ni, no, grid, 1, 10, 1e-6, 1e-6)
+	expected := algorithm.Compute(target, strategy)
+
+	strategy = NewStrategy(ni, no, grid, 1, 10, 1e-6, 1e-6)
+	strategy.BoundLevels([]uint{0, 3}, []uint{10, 10})
+	surrogate := algorithm.Compute(target, strategy)
+	assert.Equal(maxLevels(surrogate), []uint64{maxLevels(expected)[0], 3}, t)
+	assert.Equal(surrogate.Nodes <= 2*expected.Nodes, true, t)
+}
+
+func maxLevels(surrogate *interpolation.Surrogate) []uint64 {
+	levels := make([]uint64, surrogate.Inputs)
+	for i, index := range surrogate.Indices {
+		j := uint(i) % surrogate.Inputs
+		if level := internal.LEVEL_MASK & index; level > levels[j] {
+			levels[j] = level
+		}
+	}
+	return levels
+}
+
+func TestRefine(t *testing.T) {
+	fixture := &fixtureBranin
+	ni, no := fixture.surrogate.Inputs, fixture.surrogate.Outputs
+
+	count := int64(0)
+	target := func(x, y []float64) {
+		atomic.AddInt64(&count, 1)
+		fixture.target(x, y)
+	}
+
+	algorithm, strategy := prepare(fixture)
+	fine := algorithm.Compute(target, strategy)
+
+	strategy = NewStrategy(ni, no, fixture.grid, 1, 10, 1e-6, 1e-2)
+	coarse := algorithm.Compute(target, strategy)
+	assert.Equal(coarse.Nodes < fine.Nodes, true, t)
+
+	count = 0
+	_, strategy = prepare(fixture)
+	surrogate := algorithm.Refine(target, strategy, coarse, nil)
+	assert.Equal(count, int64(surrogate.Nodes-coarse.Nodes), t)
+
+	actual, expected := arrange(surrogate), arrange(fine)
+	assert.Equal(actual.Indices, expected.Indices, t)
+	assert.Close(actual.Surpluses, expected.Surpluses, 1e-10, t)
+	assert.Close(surrogate.Integral, fine.Integral, 1e-10, t)
+
+	recalibrated := func(x, y []float64) {
+		fixture.target(x, y)
+		y[0] += 2.0 * x[0] * x[1]
+	}
+	nodes := fixture.grid.Compute(coarse.Indices)
+	values := interpolation.Invoke(recalibrated, nodes, ni, no)
+	_, strategy = prepare(fixture)
+	surrogate = algorithm.Refine(recalibrated, strategy, coarse, values)
+	assert.Close(algorithm.Evaluate(surrogate, nodes), values, 1e-10, t)
+
+	count = 0
+	_, strategy = prepare(fixture)
+	strategy.(*Strategy).Limit(50, 0)
+	surrogate = algorithm.Refine(target, strategy, coarse, nil)
+	assert.Equal(count, int64(surrogate.Nodes-coarse.Nodes), t)
+	assert.Equal(count <= 50, true, t)
+	assert.Equal(count > 40, true, t)
+}
+
+func arrange(surrogate *interpolation.Surrogate) *interpolation.Surrogate {
+	ni, no := surrogate.Inputs, surrogate.Outputs
+	order := make([]uint, surrogate.Nodes)
+	for i := range order {
+		order[i] = uint(i)
+	}
+	sort.Slice(order, func(i, j int) bool {
+		a := surrogate.Indices[order[i]*ni : (order[i]+1)*ni]
+		b := surrogate.Indices[order[j]*ni : (order[j]+1)*ni]
+		for k := range a {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return false
+	})
+	result := interpolation.NewSurrogate(ni, no)
+	for _, i := range order {
+		result.Indices = append(result.Indices, surrogate.Indices[i*ni:(i+1)*ni]...)
+		result.Surpluses = append(result.Surpluses, surrogate.Surpluses[i*no:(i+1)*no]...)
+	}
+	return result
+}
+
+func TestDomain(t *testing.T) {
+	const (
+		ni = 2
+		no = 1
+	)
+
+	grid, basis := equidistant.NewClosed(ni), polynomial.NewClosed(ni, 1)
+	domain := interpolation.NewDomain([]float64{1.0, -1.0}, []float64{3.0, 2.0})
+
+	algorithm := New(ni, no, grid, basis)
+	algorithm.Restrict(domain)
+	surrogate := algorithm.Compute(func(x, y []float64) {
+		y[0] = x[0] * x[1]
+	}, NewStrategy(ni, no, grid, 1, 10, 1e-4, 1e-4))
+	assert.Equal(surrogate.Domain, domain, t)
+	assert.Close(surrogate.Integral, []float64{6.0}, 1e-14, t)
+	assert.Close(algorithm.Evaluate(surrogate, []float64{2.9, 1.9}), []float64{5.51}, 1e-14, t)
+
+	defer func() {
+		assert.Equal(recover() != nil, true, t)
+	}()
+	algorithm.Restrict(interpolation.NewDomain([]float64{1.0}, []float64{3.0}))
+}
+
+func TestEvaluateGradient(t *testing.T) {
+	const (
+		ni = 2
+		no = 1
+	)
+
+	grid, basis := equidistant.NewClosed(ni), polynomial.NewClosed(ni, 2)
+	algorithm := New(ni, no, grid, basis)
+	strategy := NewStrategy(ni, no, grid, 2, 10, 1e-10, 1e-10)
+
+	surrogate := algorithm.Compute(func(x, y []float64) {
+		y[0] = x[0]*x[1] + x[1]*x[1]
+	}, strategy)
+
+	points := []float64{0.3, 0.7, 0.9, 0.2}
+	assert.Close(algorithm.EvaluateGradient(surrogate, points),
+		[]float64{0.7, 1.7, 0.2, 1.3}, 1e-10, t)
+	assert.Close(algorithm.EvaluateHessian(surrogate, points),
+		[]float64{0.0, 1.0, 1.0, 2.0, 0.0, 1.0, 1.0, 2.0}, 1e-8, t)
+}
@@ -0,0 +1,20 @@
+package global
+
+import (
+	"github.com/ready-steady/adapt/algorithm"
+	"github.com/ready-steady/adapt/algorithm/internal/core"
+)
+
+// Process is a step-wise interpolation process. In each iteration, the process
+// hands out the nodes of the current state and waits for the values of the
+// target at these nodes, which can be computed elsewhere, for instance, by an
+// external scheduler. The strategy is advanced only when all the values of the
+// current state have been supplied.
+type Process struct {
+	*core.Process
+}
+
+// Start begins a step-wise interpolation process.
+func (self *Algorithm) Start(strategy algorithm.Strategy) *Process {
+	return &Process{core.Start(self.config(), strategy)}
+}
@@ -1,6 +1,8 @@
 package global
 
 import (
+	"time"
+
 	"github.com/ready-steady/adapt/algorithm"
 	"github.com/ready-steady/adapt/algorithm/internal"
 	"github.com/ready-steady/adapt/grid"
@@ -13,13 +15,21 @@ type Strategy struct {
 
 	guide Guide
 
-	lmin uint
-	lmax uint
+	lmin  uint
+	lmax  uint
+	limit *internal.Limit
+
+	norm    algorithm.Norm
+	weights []float64
+
+	weight float64
 
 	priority []float64
 	accuracy []float64
+	costs    map[uint]uint
 
 	active    *internal.Active
+	budget    *internal.Budget
 	threshold *internal.Threshold
 }
 
@@ -32,38 +42,126 @@ type Guide interface {
 func NewStrategy(inputs, outputs uint, guide Guide, minLevel, maxLevel uint,
 	absoluteError, relativeError float64) *Strategy {
 
+	limit := internal.NewLimit(inputs)
+	active := internal.NewActive(inputs)
+	active.Restrict(limit, maxLevel)
+
 	return &Strategy{
 		ni: inputs,
 		no: outputs,
 
 		guide: guide,
 
-		lmin: minLevel,
-		lmax: maxLevel,
+		lmin:  minLevel,
+		lmax:  maxLevel,
+		limit: limit,
 
-		active:    internal.NewActive(inputs),
+		norm: algorithm.NormSum,
+
+		weight: -1.0,
+
+		active:    active,
+		budget:    internal.NewBudget(0, 0),
 		threshold: internal.NewThreshold(outputs, absoluteError, relativeError),
 	}
 }
 
+// Limit sets the maximum number of nodes, which is the number of evaluations of
+// the target, and the maximum running time. A zero value means no limit. When
+// the number of nodes is limited, the level indices with the highest
+// priorities are refined first, and the refinements that do not fit in the
+// remaining budget are skipped. When resuming, only the new nodes are counted.
+func (self *Strategy) Limit(nodes uint, duration time.Duration) {
+	self.budget = internal.NewBudget(nodes, duration)
+}
+
+// Timed checks if the running time is limited.
+func (self *Strategy) Timed() bool {
+	return self.budget.Timed()
+}
+
+// BoundLevels sets the minimal and maximal levels of each dimension, which
+// apply in addition to the minimal and maximal levels given at creation.
+func (self *Strategy) BoundLevels(minLevels, maxLevels []uint) {
+	self.limit.Bound(minLevels, maxLevels)
+}
+
+// WeighLevels sets the weights of the dimensions in the level norm that the
+// minimal and maximal levels given at creation are compared with. By default,
+// the norm is the sum of the levels of the dimensions.
+func (self *Strategy) WeighLevels(weights []float64) {
+	self.limit.Weigh(weights)
+}
+
+// Weigh sets the weights of the outputs used for scoring nodes.
+func (self *Strategy) Weigh(weights []float64) {
+	if uint(len(weights)) != self.no {
+		panic("the number of weights should be equal to the number of outputs")
+	}
+	self.weights = append([]float64(nil), weights...)
+}
+
+// Collapse sets the norm used for collapsing the weighted outputs of a node
+// into a single score. The default is the sum norm.
+func (self *Strategy) Collapse(norm algorithm.Norm) {
+	self.norm = norm
+}
+
+// Tolerate sets the absolute and relative errors of each output, which
+// replace the errors given at creation.
+func (self *Strategy) Tolerate(absolute, relative []float64) {
+	self.threshold.Tolerate(absolute, relative)
+}
+
 func (self *Strategy) First(surrogate *algorithm.Surrogate) *algorithm.State {
-	return self.initiate(self.active.First(), surrogate)
+	self.budget.Start()
+	self.costs = make(map[uint]uint)
+	state := self.initiate(self.active.First(), surrogate)
+	self.budget.Consume(uint(len(state.Indices)) / self.ni)
+	return state
 }
 
 func (self *Strategy) Next(state *algorithm.State,
 	surrogate *algorithm.Surrogate) *algorithm.State {
 
+	return self.next(state, surrogate, self.consume, func() bool {
+		return self.threshold.Check(self.accuracy, self.active.Positions)
+	})
+}
+
+func (self *Strategy) Resume(state *algorithm.State,
+	surrogate *algorithm.Surrogate) *algorithm.State {
+
+	return self.resume(state, surrogate, self.Next)
+}
+
+func (self *Strategy) Report(progress *algorithm.Progress) {
+	progress.Active = uint(len(self.active.Positions))
+	progress.Threshold = self.threshold.Values()
+}
+
+func (self *Strategy) Score(element *algorithm.Element) float64 {
+	return self.norm.Compute(element.Surplus, self.weights)
+}
+
+func (self *Strategy) next(state *algorithm.State, surrogate *algorithm.Surrogate,
+	consume func(*algorithm.State), check func() bool) *algorithm.State {
+
 	exclude := make(map[uint]bool)
 	for {
-		self.consume(state)
-		if self.threshold.Check(self.accuracy, self.active.Positions) {
+		consume(state)
+		if check() {
+			return nil
+		}
+		if self.budget.Expired() {
 			return nil
 		}
-		k := internal.Choose(self.priority, self.active.Positions, exclude)
+		k := self.choose(exclude)
 		if k == internal.None {
 			return nil
 		}
 		lndices := self.active.Next(k)
+		self.invalidate(uint(len(lndices)) / self.ni)
 		if len(lndices) > 0 {
 			self.active.Drop(k)
 		} else {
@@ -71,13 +169,32 @@ func (self *Strategy) Next(state *algorithm.State,
 		}
 		state = self.initiate(lndices, surrogate)
 		if len(state.Indices) > 0 {
+			self.budget.Consume(uint(len(state.Indices)) / self.ni)
 			return state
 		}
 	}
 }
 
-func (self *Strategy) Score(element *algorithm.Element) float64 {
-	return internal.SumAbsolute(element.Surplus)
+func (self *Strategy) resume(state *algorithm.State, surrogate *algorithm.Surrogate,
+	next func(*algorithm.State, *algorithm.Surrogate) *algorithm.State) *algorithm.State {
+
+	self.budget.Start()
+	self.costs = make(map[uint]uint)
+	self.active.Restore(state.Lndices)
+	return next(state, surrogate)
+}
+
+func (self *Strategy) choose(exclude map[uint]bool) uint {
+	for {
+		k := internal.Choose(self.rank(exclude), self.active.Positions, exclude)
+		if k == internal.None || !self.budget.Limited() {
+			return k
+		}
+		if self.budget.Fits(self.cost(k)) {
+			return k
+		}
+		exclude[k] = true
+	}
 }
 
 func (self *Strategy) consume(state *algorithm.State) {
@@ -92,14 +209,12 @@ func (self *Strategy) consume(state *algorithm.State) {
 	self.accuracy = append(self.accuracy, make([]float64, nn*no)...)
 	accuracy := self.accuracy[na:]
 
-	levels := internal.Levelize(state.Lndices, ni)
-
 	for i, o := uint(0), uint(0); i < nn; i++ {
 		count := state.Counts[i]
-		if levels[i] < uint64(self.lmin) {
+		if self.limit.Below(state.Lndices[i*ni:(i+1)*ni], self.lmin) {
 			priority[i] = internal.Infinity
 			internal.Set(accuracy[i*no:(i+1)*no], internal.Infinity)
-		} else if levels[i] < uint64(self.lmax) {
+		} else if count > 0 && !self.limit.Reached(state.Lndices[i*ni:(i+1)*ni], self.lmax) {
 			priority[i] = internal.Average(state.Scores[o:(o + count)])
 			self.threshold.Compress(accuracy[i*no:(i+1)*no],
 				state.Surpluses[o*no:(o+count)*no])
@@ -115,3 +230,31 @@ func (self *Strategy) initiate(lndices []uint64, _ *algorithm.Surrogate) (state
 	state.Indices, state.Counts = internal.Index(self.guide, lndices, self.ni)
 	return
 }
+
+func (self *Strategy) cost(k uint) uint {
+	if cost, found := self.costs[k]; found {
+		return cost
+	}
+	cost := self.count(k)
+	self.costs[k] = cost
+	return cost
+}
+
+func (self *Strategy) invalidate(nn uint) {
+	no := uint(len(self.active.Lndices))/self.ni - nn
+	for k := no; k < no+nn; k++ {
+		delete(self.costs, k)
+		for _, l := range self.active.Around(k) {
+			delete(self.costs, l)
+		}
+	}
+}
+
+func (self *Strategy) count(k uint) uint {
+	_, counts := internal.Index(self.guide, self.active.Peek(k), self.ni)
+	total := uint(0)
+	for _, count := range counts {
+		total += count
+	}
+	return total
+}
@@ -0,0 +1,24 @@
+package global
+
+import (
+	"github.com/ready-steady/adapt/algorithm"
+	"github.com/ready-steady/adapt/algorithm/internal/core"
+)
+
+// Refine constructs an interpolant for a function starting from a previously
+// computed one, for instance, with a tighter tolerance or for a recalibrated
+// function. The values of the function at the nodes of the previous
+// interpolant are taken from the values argument, which is ordered as the
+// nodes, or, if it is nil, from the previous interpolant itself.
+//
+// The strategies of the package continue from the previous interpolant: its
+// level indices are restored, the ones having admissible forward neighbors
+// outside of it are activated, and the function is invoked only at the nodes
+// that are new. The nodes of the result are ordered by level index. Strategies
+// not implementing algorithm.Resumer repeat the interpolation process using the
+// known values instead.
+func (self *Algorithm) Refine(target algorithm.Target, strategy algorithm.Strategy,
+	surrogate *algorithm.Surrogate, values []float64) *algorithm.Surrogate {
+
+	return core.Refine(self.config(), target, strategy, surrogate, values)
+}
@@ -0,0 +1,71 @@
+package global
+
+import (
+	"math"
+
+	"github.com/ready-steady/adapt/algorithm/internal"
+)
+
+// NewWorkStrategy creates a work-weighted strategy.
+//
+// The priority of an active level index balances its error indicator against
+// the work of refining it, which is the number of nodes of its admissible
+// forward neighbors, following Gerstner and Griebel. Both quantities are
+// normalized with respect to the active level indices, and the priority is
+//
+//	max(weight × error / maximal error, (1 - weight) × minimal work / work).
+//
+// A weight of one corresponds to the greedy error-based refinement of the basic
+// strategy, and a weight of zero corresponds to the cheapest-first, Smolyak-like
+// refinement.
+func NewWorkStrategy(inputs, outputs uint, guide Guide, minLevel, maxLevel uint,
+	absoluteError, relativeError, weight float64) *Strategy {
+
+	if weight < 0.0 || weight > 1.0 {
+		panic("the weight should be between zero and one")
+	}
+
+	strategy := NewStrategy(inputs, outputs, guide, minLevel, maxLevel,
+		absoluteError, relativeError)
+	strategy.weight = weight
+	return strategy
+}
+
+func (self *Strategy) rank(exclude map[uint]bool) []float64 {
+	if self.weight < 0.0 {
+		return self.priority
+	}
+
+	candidates := []uint{}
+	for k := range self.active.Positions {
+		if exclude[k] || !(self.priority[k] > 0.0) {
+			continue
+		}
+		if math.IsInf(self.priority[k], 1) {
+			// The level index has to be refined regardless of its cost.
+			return self.priority
+		}
+		candidates = append(candidates, k)
+	}
+
+	error, work := 0.0, internal.Infinity
+	costs := make(map[uint]float64, len(candidates))
+	for _, k := range candidates {
+		costs[k] = float64(self.cost(k))
+		error = math.Max(error, self.priority[k])
+		if costs[k] > 0.0 {
+			work = math.Min(work, costs[k])
+		}
+	}
+
+	priority := make([]float64, len(self.priority))
+	for _, k := range candidates {
+		if costs[k] == 0.0 {
+			priority[k] = internal.Infinity
+			continue
+		}
+		priority[k] = math.Max(self.weight*self.priority[k]/error,
+			(1.0-self.weight)*work/costs[k])
+	}
+	return priority
+}
@@ -0,0 +1,196 @@
+// Package hierarchy provides conversions between nodal values and
+// hierarchical surpluses.
+//
+// The conversions are performed using unidirectional algorithms, which sweep
+// through the dimensions one at a time and work with one-dimensional poles of
+// nodes. The unidirectional algorithms require the index set to contain the
+// parents of each index in each dimension and the basis functions to vanish at
+// the nodes that are neither their descendants nor on the levels present in the
+// corresponding pole, which is the case for the polynomial bases with index
+// sets closed under taking parents and for the Lagrange basis with index sets
+// composed of complete levels, as produced by the global algorithm. If the index
+// set is not closed under taking parents, the conversions fall back to direct
+// summation.
+package hierarchy
+
+import (
+	"sort"
+
+	"github.com/ready-steady/adapt/algorithm"
+	"github.com/ready-steady/adapt/algorithm/internal"
+	"github.com/ready-steady/adapt/basis"
+	"github.com/ready-steady/adapt/grid"
+
+	rinternal "github.com/ready-steady/adapt/internal"
+)
+
+// Basis is an interpolation basis.
+type Basis interface {
+	basis.Computer
+	basis.Integrator
+}
+
+// Grid is an interpolation grid.
+type Grid interface {
+	grid.Computer
+	grid.Parenter
+}
+
+// Hierarchize constructs an interpolant on the unit hypercube from the values
+// of a function at the nodes of a set of indices.
+func Hierarchize(grid Grid, basis Basis, indices []uint64, values []float64,
+	ni, no uint) *algorithm.Surrogate {
+
+	nn := uint(len(indices)) / ni
+	if uint(len(values)) != nn*no {
+		panic("the number of values does not match the number of nodes")
+	}
+
+	surpluses := append([]float64(nil), values...)
+	if closed(grid, indices, ni) {
+		sweep(grid, basis, indices, surpluses, ni, no, true)
+	} else {
+		hierarchize(grid, basis, indices, surpluses, ni, no)
+	}
+
+	surrogate := algorithm.NewSurrogate(ni, no)
+	surrogate.Push(indices, surpluses, internal.Measure(basis, indices, ni))
+	return surrogate
+}
+
+// Dehierarchize computes the values of an interpolant on the unit hypercube at
+// its nodes.
+func Dehierarchize(grid Grid, basis Basis, surrogate *algorithm.Surrogate) []float64 {
+	ni, no := surrogate.Inputs, surrogate.Outputs
+	if closed(grid, surrogate.Indices, ni) {
+		values := append([]float64(nil), surrogate.Surpluses...)
+		sweep(grid, basis, surrogate.Indices, values, ni, no, false)
+		return values
+	}
+	return internal.Estimate(basis, surrogate.Indices, surrogate.Surpluses,
+		grid.Compute(surrogate.Indices), ni, no)
+}
+
+func closed(parenter grid.Parenter, indices []uint64, ni uint) bool {
+	nn := uint(len(indices)) / ni
+	history := internal.NewHistory(ni)
+	for i := uint(0); i < nn; i++ {
+		history.Set(indices[i*ni:(i+1)*ni], i)
+	}
+	index := make([]uint64, ni)
+	for i := uint(0); i < nn; i++ {
+		copy(index, indices[i*ni:(i+1)*ni])
+		for j := uint(0); j < ni; j++ {
+			level := rinternal.LEVEL_MASK & index[j]
+			if level == 0 {
+				continue
+			}
+			order := index[j] >> rinternal.LEVEL_SIZE
+			plevel, porder := parenter.Parent(level, order)
+			index[j] = porder<<rinternal.LEVEL_SIZE | plevel
+			if _, found := history.Get(index); !found {
+				return false
+			}
+			index[j] = indices[i*ni+j]
+		}
+	}
+	return true
+}
+
+func hierarchize(grid Grid, basis Basis, indices []uint64, surpluses []float64, ni, no uint) {
+	nn := uint(len(indices)) / ni
+	levels := internal.Levelize(indices, ni)
+	order := make([]uint, nn)
+	for i := range order {
+		order[i] = uint(i)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return levels[order[i]] < levels[order[j]]
+	})
+
+	done, doneSurpluses := []uint64{}, []float64{}
+	for k := uint(0); k < nn; {
+		m := k
+		group := []uint64{}
+		for ; m < nn && levels[order[m]] == levels[order[k]]; m++ {
+			group = append(group, indices[order[m]*ni:(order[m]+1)*ni]...)
+		}
+		estimates := internal.Estimate(basis, done, doneSurpluses, grid.Compute(group), ni, no)
+		for l := k; l < m; l++ {
+			i := order[l]
+			for j := uint(0); j < no; j++ {
+				surpluses[i*no+j] -= estimates[(l-k)*no+j]
+			}
+			doneSurpluses = append(doneSurpluses, surpluses[i*no:(i+1)*no]...)
+		}
+		done = append(done, group...)
+		k = m
+	}
+}
+
+func sweep(grid Grid, basis Basis, indices []uint64, data []float64, ni, no uint,
+	forward bool) {
+
+	nn := uint(len(indices)) / ni
+	nodes := grid.Compute(indices)
+	hash := internal.NewHash(ni)
+
+	index, point := make([]uint64, ni), make([]float64, ni)
+	compute := func(d, j, i uint) float64 {
+		index[d], point[d] = indices[j*ni+d], nodes[i*ni+d]
+		return basis.Compute(index, point)
+	}
+
+	for d := uint(0); d < ni; d++ {
+		keys, poles := []string{}, make(map[string][]uint)
+		for i := uint(0); i < nn; i++ {
+			copy(index, indices[i*ni:(i+1)*ni])
+			index[d] = 0
+			key := hash.Key(index)
+			if _, found := poles[key]; !found {
+				keys = append(keys, key)
+			}
+			poles[key] = append(poles[key], i)
+		}
+
+		for i := range index {
+			index[i] = 0
+		}
+
+		for _, key := range keys {
+			pole := poles[key]
+			sort.SliceStable(pole, func(i, j int) bool {
+				return rinternal.LEVEL_MASK&indices[pole[i]*ni+d] <
+					rinternal.LEVEL_MASK&indices[pole[j]*ni+d]
+			})
+			np := len(pole)
+			for s := 0; s < np; s++ {
+				// Dehierarchization needs the original coefficients of the lower
+				// levels, so the pole is traversed backward.
+				a := s
+				if !forward {
+					a = np - 1 - s
+				}
+				i := pole[a]
+				level := rinternal.LEVEL_MASK & indices[i*ni+d]
+				for b := 0; b < np; b++ {
+					j := pole[b]
+					if rinternal.LEVEL_MASK&indices[j*ni+d] >= level {
+						break
+					}
+					weight := compute(d, j, i)
+					if weight == 0.0 {
+						continue
+					}
+					if forward {
+						weight = -weight
+					}
+					for l := uint(0); l < no; l++ {
+						data[i*no+l] += weight * data[j*no+l]
+					}
+				}
+			}
+			index[d] = 0
+		}
+	}
+}
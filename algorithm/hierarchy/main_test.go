@@ -0,0 +1,96 @@
+package hierarchy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/adapt/algorithm/global"
+	"github.com/ready-steady/adapt/algorithm/local"
+	"github.com/ready-steady/adapt/basis/lagrange"
+	"github.com/ready-steady/adapt/basis/polynomial"
+	"github.com/ready-steady/adapt/grid/chebyshev"
+	"github.com/ready-steady/adapt/grid/equidistant"
+	"github.com/ready-steady/assert"
+)
+
+func target(x, y []float64) {
+	y[0] = math.Exp(-5.0*(x[0]-0.3)*(x[0]-0.3)) * math.Sin(3.0*x[1])
+	y[1] = x[0]*x[1] + x[1]
+}
+
+func TestClosed(t *testing.T) {
+	const (
+		ni = 2
+		no = 2
+	)
+
+	grid, basis := equidistant.NewClosed(ni), polynomial.NewClosed(ni, 2)
+	algorithm := local.New(ni, no, grid, basis)
+	expected := algorithm.Compute(target, local.NewStrategy(ni, no, grid, 1, 8, 1e-4))
+
+	nodes := grid.Compute(expected.Indices)
+	values := make([]float64, expected.Nodes*no)
+	for i := uint(0); i < expected.Nodes; i++ {
+		target(nodes[i*ni:(i+1)*ni], values[i*no:(i+1)*no])
+	}
+
+	surrogate := Hierarchize(grid, basis, expected.Indices, values, ni, no)
+	assert.Close(surrogate.Surpluses, expected.Surpluses, 1e-12, t)
+	assert.Close(surrogate.Integral, expected.Integral, 1e-12, t)
+	assert.Close(Dehierarchize(grid, basis, surrogate), values, 1e-12, t)
+}
+
+func TestLagrange(t *testing.T) {
+	const (
+		ni = 2
+		no = 2
+	)
+
+	grid, basis := chebyshev.NewClosed(ni), lagrange.NewClosed(ni)
+	algorithm := global.New(ni, no, grid, basis)
+	expected := algorithm.Compute(target, global.NewStrategy(ni, no, grid, 1, 6, 1e-6, 1e-4))
+	assert.Equal(closed(grid, expected.Indices, ni), true, t)
+
+	nodes := grid.Compute(expected.Indices)
+	values := make([]float64, expected.Nodes*no)
+	for i := uint(0); i < expected.Nodes; i++ {
+		target(nodes[i*ni:(i+1)*ni], values[i*no:(i+1)*no])
+	}
+
+	surrogate := Hierarchize(grid, basis, expected.Indices, values, ni, no)
+	assert.Close(surrogate.Surpluses, expected.Surpluses, 1e-12, t)
+	assert.Close(Dehierarchize(grid, basis, surrogate), values, 1e-12, t)
+}
+
+func TestOpen(t *testing.T) {
+	const (
+		ni = 2
+		no = 2
+	)
+
+	grid, basis := equidistant.NewOpen(ni), polynomial.NewOpen(ni, 1)
+	algorithm := local.New(ni, no, grid, basis)
+	expected := algorithm.Compute(target, local.NewStrategy(ni, no, grid, 1, 8, 1e-4))
+
+	nodes := grid.Compute(expected.Indices)
+	values := make([]float64, expected.Nodes*no)
+	for i := uint(0); i < expected.Nodes; i++ {
+		target(nodes[i*ni:(i+1)*ni], values[i*no:(i+1)*no])
+	}
+
+	surrogate := Hierarchize(grid, basis, expected.Indices, values, ni, no)
+	assert.Close(surrogate.Surpluses, expected.Surpluses, 1e-12, t)
+	assert.Close(Dehierarchize(grid, basis, surrogate), values, 1e-12, t)
+
+	indices := expected.Indices[:0:0]
+	for i := uint(0); i < expected.Nodes; i++ {
+		if i != 1 {
+			indices = append(indices, expected.Indices[i*ni:(i+1)*ni]...)
+		}
+	}
+	assert.Equal(closed(grid, indices, ni), false, t)
+
+	values = append(values[:no:no], values[2*no:]...)
+	surrogate = Hierarchize(grid, basis, indices, values, ni, no)
+	assert.Close(Dehierarchize(grid, basis, surrogate), values, 1e-12, t)
+}
@@ -1,8 +1,13 @@
 package hybrid
 
 import (
+	"math"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/ready-steady/adapt/basis/polynomial"
+	"github.com/ready-steady/adapt/grid/equidistant"
 	"github.com/ready-steady/assert"
 
 	interpolation "github.com/ready-steady/adapt/algorithm"
@@ -20,3 +25,93 @@ func TestBranin(t *testing.T) {
 	values := algorithm.Evaluate(surrogate, fixture.points)
 	assert.Close(values, fixture.values, 0.1, t)
 }
+
+func TestLimit(t *testing.T) {
+	fixture := &fixtureBranin
+
+	algorithm, strategy := prepare(fixture)
+	strategy.(*Strategy).Limit(100, 0)
+	surrogate := algorithm.Compute(fixture.target, strategy)
+	assert.Equal(surrogate.Nodes <= 100, true, t)
+	assert.Equal(surrogate.Nodes > 90, true, t)
+	assert.Equal(interpolation.Validate(surrogate.Indices, surrogate.Inputs,
+		fixture.grid), true, t)
+
+	algorithm, strategy = prepare(fixture)
+	strategy.(*Strategy).Limit(0, time.Nanosecond)
+	surrogate = algorithm.Compute(fixture.target, strategy)
+	assert.Equal(surrogate.Nodes, uint(1), t)
+}
+
+func TestTolerateScores(t *testing.T) {
+	fixture := &fixtureBranin
+	ni := fixture.surrogate.Inputs
+	inf := math.Inf(1.0)
+
+	algorithm := New(ni, 1, fixture.grid, fixture.basis)
+	strategy := NewStrategy(ni, 1, fixture.grid, 1, 10, 1e-6, 1e-3, 1e-2)
+	expected := algorithm.Compute(fixture.target, strategy)
+
+	algorithm = New(ni, 2, fixture.grid, fixture.basis)
+	strategy = NewStrategy(ni, 2, fixture.grid, 1, 10, 1e-6, 1e-3, 42.0)
+	strategy.Tolerate([]float64{1e-6, inf}, []float64{1e-3, inf})
+	strategy.TolerateScores([]float64{1e-2, inf})
+	strategy.Collapse(interpolation.NormSum)
+	surrogate := algorithm.Compute(func(x, y []float64) {
+		fixture.target(x, y[:1])
+		y[1] = 1e6 * y[0]
+	}, strategy)
+	assert.Equal(surrogate.Indices, expected.Indices, t)
+}
+
+func TestDomain(t *testing.T) {
+	const (
+		ni = 2
+		no = 1
+	)
+
+	grid, basis := equidistant.NewClosed(ni), polynomial.NewClosed(ni, 1)
+	domain := interpolation.NewDomain([]float64{1.0, -1.0}, []float64{3.0, 2.0})
+
+	algorithm := New(ni, no, grid, basis)
+	algorithm.Restrict(domain)
+	surrogate := algorithm.Compute(func(x, y []float64) {
+		y[0] = x[0] * x[1]
+	}, NewStrategy(ni, no, grid, 1, 10, 1e-4, 1e-4, 1e-6))
+	assert.Equal(surrogate.Domain, domain, t)
+	assert.Close(surrogate.Integral, []float64{6.0}, 1e-14, t)
+	assert.Close(algorithm.Evaluate(surrogate, []float64{2.9, 1.9}), []float64{5.51}, 1e-14, t)
+}
+
+func TestRefine(t *testing.T) {
+	fixture := &fixtureBranin
+	ni, no := fixture.surrogate.Inputs, fixture.surrogate.Outputs
+
+	count := int64(0)
+	target := func(x, y []float64) {
+		atomic.AddInt64(&count, 1)
+		fixture.target(x, y)
+	}
+
+	algorithm, strategy := prepare(fixture)
+	coarse := algorithm.Compute(target, NewStrategy(ni, no, fixture.grid, 1, 10,
+		1e-1, 1e-1, 1e-3))
+
+	count = 0
+	surrogate := algorithm.Refine(target, strategy, coarse, nil)
+	assert.Equal(surrogate.Nodes > coarse.Nodes, true, t)
+	assert.Equal(count, int64(surrogate.Nodes-coarse.Nodes), t)
+	assert.Equal(interpolation.Validate(surrogate.Indices, surrogate.Inputs,
+		fixture.grid), true, t)
+
+	values := algorithm.Evaluate(surrogate, fixture.points)
+	assert.Close(values, fixture.values, 0.1, t)
+
+	count = 0
+	_, strategy = prepare(fixture)
+	strategy.(*Strategy).Limit(50, 0)
+	surrogate = algorithm.Refine(target, strategy, coarse, nil)
+	assert.Equal(count, int64(surrogate.Nodes-coarse.Nodes), t)
+	assert.Equal(count <= 50, true, t)
+	assert.Equal(count > 40, true, t)
+}
@@ -1,6 +1,8 @@
 package hybrid
 
 import (
+	"time"
+
 	"github.com/ready-steady/adapt/algorithm"
 	"github.com/ready-steady/adapt/algorithm/internal"
 	"github.com/ready-steady/adapt/grid"
@@ -13,11 +15,18 @@ type Strategy struct {
 
 	guide Guide
 
-	lmin uint
-	lmax uint
-	εs   float64
+	lmin  uint
+	lmax  uint
+	limit *internal.Limit
+	εs    float64
+
+	norm    algorithm.Norm
+	weights []float64
+	errors  []float64
+	scale   []float64
 
 	active    *internal.Active
+	budget    *internal.Budget
 	threshold *internal.Threshold
 	hash      *internal.Hash
 	unique    *internal.Unique
@@ -42,17 +51,25 @@ type Guide interface {
 func NewStrategy(inputs, outputs uint, guide Guide, minLevel, maxLevel uint,
 	absoluteError, relativeError, scoreError float64) *Strategy {
 
+	limit := internal.NewLimit(inputs)
+	active := internal.NewActive(inputs)
+	active.Restrict(limit, maxLevel)
+
 	return &Strategy{
 		ni: inputs,
 		no: outputs,
 
 		guide: guide,
 
-		lmin: minLevel,
-		lmax: maxLevel,
-		εs:   scoreError,
+		lmin:  minLevel,
+		lmax:  maxLevel,
+		limit: limit,
+		εs:    scoreError,
 
-		active:    internal.NewActive(inputs),
+		norm: algorithm.NormMaximum,
+
+		active:    active,
+		budget:    internal.NewBudget(0, 0),
 		threshold: internal.NewThreshold(outputs, absoluteError, relativeError),
 		hash:      internal.NewHash(inputs),
 		unique:    internal.NewUnique(inputs),
@@ -62,8 +79,72 @@ func NewStrategy(inputs, outputs uint, guide Guide, minLevel, maxLevel uint,
 	}
 }
 
+// Limit sets the maximum number of nodes, which is the number of evaluations of
+// the target, and the maximum running time. A zero value means no limit. When
+// the number of nodes is limited, the level indices with the highest
+// priorities are refined first, and the refinements that do not fit in the
+// remaining budget are skipped. When resuming, only the new nodes are counted.
+func (self *Strategy) Limit(nodes uint, duration time.Duration) {
+	self.budget = internal.NewBudget(nodes, duration)
+}
+
+// Timed checks if the running time is limited.
+func (self *Strategy) Timed() bool {
+	return self.budget.Timed()
+}
+
+// BoundLevels sets the minimal and maximal levels of each dimension, which
+// apply in addition to the minimal and maximal levels given at creation.
+func (self *Strategy) BoundLevels(minLevels, maxLevels []uint) {
+	self.limit.Bound(minLevels, maxLevels)
+}
+
+// WeighLevels sets the weights of the dimensions in the level norm that the
+// minimal and maximal levels given at creation are compared with. By default,
+// the norm is the sum of the levels of the dimensions.
+func (self *Strategy) WeighLevels(weights []float64) {
+	self.limit.Weigh(weights)
+}
+
+// Weigh sets the weights of the outputs used for scoring nodes.
+func (self *Strategy) Weigh(weights []float64) {
+	if uint(len(weights)) != self.no {
+		panic("the number of weights should be equal to the number of outputs")
+	}
+	self.weights = append([]float64(nil), weights...)
+	self.rescale()
+}
+
+// Collapse sets the norm used for collapsing the weighted outputs of a node
+// into a single score. The default is the maximum norm.
+func (self *Strategy) Collapse(norm algorithm.Norm) {
+	self.norm = norm
+}
+
+// Tolerate sets the absolute and relative errors of each output, which
+// replace the errors given at creation.
+func (self *Strategy) Tolerate(absolute, relative []float64) {
+	self.threshold.Tolerate(absolute, relative)
+}
+
+// TolerateScores sets the score errors of the outputs, which replace the score
+// error given at creation. A node is then refined if the norm of its weighted
+// surpluses divided by the errors and multiplied by the volume of its basis
+// function reaches one.
+func (self *Strategy) TolerateScores(errors []float64) {
+	if uint(len(errors)) != self.no {
+		panic("the number of errors should be equal to the number of outputs")
+	}
+	self.errors = append([]float64(nil), errors...)
+	self.εs = 1.0
+	self.rescale()
+}
+
 func (self *Strategy) First(surrogate *algorithm.Surrogate) *algorithm.State {
-	return self.initiate(self.active.First(), surrogate)
+	self.budget.Start()
+	state := self.initiate(self.active.First(), surrogate)
+	self.budget.Consume(uint(len(state.Indices)) / self.ni)
+	return state
 }
 
 func (self *Strategy) Next(state *algorithm.State,
@@ -75,7 +156,10 @@ func (self *Strategy) Next(state *algorithm.State,
 		if self.threshold.Check(self.accuracy, self.active.Positions) {
 			return nil
 		}
-		k := internal.Choose(self.priority, self.active.Positions, exclude)
+		if self.budget.Expired() {
+			return nil
+		}
+		k := self.choose(exclude, surrogate)
 		if k == internal.None {
 			return nil
 		}
@@ -87,13 +171,57 @@ func (self *Strategy) Next(state *algorithm.State,
 		}
 		state = self.initiate(lndices, surrogate)
 		if len(state.Indices) > 0 {
+			self.budget.Consume(uint(len(state.Indices)) / self.ni)
 			return state
 		}
 	}
 }
 
+// Resume continues from the active level indices of a previously computed
+// interpolant. The nodes of the level indices that are already present are kept
+// as they are, since adding nodes to them would alter the surpluses of the
+// nodes of the level indices above.
+func (self *Strategy) Resume(state *algorithm.State,
+	surrogate *algorithm.Surrogate) *algorithm.State {
+
+	self.budget.Start()
+	self.active.Restore(state.Lndices)
+	return self.Next(state, surrogate)
+}
+
+func (self *Strategy) Report(progress *algorithm.Progress) {
+	progress.Active = uint(len(self.active.Positions))
+	progress.Threshold = self.threshold.Values()
+}
+
 func (self *Strategy) Score(element *algorithm.Element) float64 {
-	return internal.MaxAbsolute(element.Surplus) * element.Volume
+	return self.norm.Compute(element.Surplus, self.scale) * element.Volume
+}
+
+func (self *Strategy) rescale() {
+	self.scale = make([]float64, self.no)
+	for i := range self.scale {
+		self.scale[i] = 1.0
+		if self.weights != nil {
+			self.scale[i] *= self.weights[i]
+		}
+		if self.errors != nil {
+			self.scale[i] /= self.errors[i]
+		}
+	}
+}
+
+func (self *Strategy) choose(exclude map[uint]bool, surrogate *algorithm.Surrogate) uint {
+	for {
+		k := internal.Choose(self.priority, self.active.Positions, exclude)
+		if k == internal.None || !self.budget.Limited() {
+			return k
+		}
+		if self.budget.Fits(self.count(k, surrogate)) {
+			return k
+		}
+		exclude[k] = true
+	}
 }
 
 func (self *Strategy) consume(state *algorithm.State) {
@@ -116,14 +244,12 @@ func (self *Strategy) consume(state *algorithm.State) {
 	scores := self.scores[ns:]
 
 	groups := state.Data.([][]uint64)
-	levels := internal.Levelize(state.Lndices, ni)
-
 	for i, o := uint(0), uint(0); i < nn; i++ {
 		count := state.Counts[i]
-		if levels[i] < uint64(self.lmin) {
+		if self.limit.Below(state.Lndices[i*ni:(i+1)*ni], self.lmin) {
 			internal.Set(accuracy[i*no:(i+1)*no], internal.Infinity)
 			internal.Set(scores[o:(o+count)], internal.Infinity)
-		} else if levels[i] < uint64(self.lmax) {
+		} else if !self.limit.Reached(state.Lndices[i*ni:(i+1)*ni], self.lmax) {
 			self.threshold.Compress(accuracy[i*no:(i+1)*no],
 				state.Surpluses[o*no:(o+count)*no])
 			copy(scores[o:(o+count)], state.Scores[o:(o+count)])
@@ -147,9 +273,9 @@ func (self *Strategy) consume(state *algorithm.State) {
 			scope[j] = k
 		}
 		scopes[i] = scope
-		if levels[i] < uint64(self.lmin) {
+		if self.limit.Below(state.Lndices[i*ni:(i+1)*ni], self.lmin) {
 			priority[i] = internal.Infinity
-		} else if levels[i] < uint64(self.lmax) {
+		} else if !self.limit.Reached(state.Lndices[i*ni:(i+1)*ni], self.lmax) {
 			for _, j := range scope {
 				priority[i] += self.scores[j]
 			}
@@ -211,3 +337,11 @@ func (self *Strategy) initiate(lndices []uint64,
 	}
 	return
 }
+
+func (self *Strategy) count(k uint, surrogate *algorithm.Surrogate) uint {
+	indices := []uint64{}
+	for _, group := range self.index(self.active.Peek(k), surrogate) {
+		indices = append(indices, group...)
+	}
+	return self.unique.Count(indices)
+}
@@ -7,6 +7,8 @@ type Active struct {
 
 	ni uint
 
+	limit    *Limit
+	lmax     uint
 	history  *History
 	forward  reference
 	backward reference
@@ -21,6 +23,11 @@ func NewActive(ni uint) *Active {
 	}
 }
 
+// Restrict makes the level indices respect a limit with a maximal level norm.
+func (self *Active) Restrict(limit *Limit, lmax uint) {
+	self.limit, self.lmax = limit, lmax
+}
+
 // Drop deactivates a level index.
 func (self *Active) Drop(k uint) {
 	delete(self.Positions, k)
@@ -35,24 +42,100 @@ func (self *Active) First() []uint64 {
 	return self.Lndices
 }
 
-// Next returns admissible forward neighbors of a level index.
+// Restore replaces the level indices considered so far with a downward-closed
+// set of level indices and activates those that have admissible forward
+// neighbors outside the set.
+func (self *Active) Restore(lndices []uint64) {
+	ni := self.ni
+	nn := uint(len(lndices)) / ni
+
+	self.Lndices = append([]uint64(nil), lndices...)
+	self.Positions = make(map[uint]bool)
+	self.history = NewHistory(ni)
+	self.forward, self.backward = make(reference), make(reference)
+
+	for k := uint(0); k < nn; k++ {
+		self.history.Set(self.Lndices[k*ni:(k+1)*ni], k)
+	}
+	for k := uint(0); k < nn; k++ {
+		lndex := self.Lndices[k*ni : (k+1)*ni]
+		for i := uint(0); i < ni; i++ {
+			if lndex[i] == 0 {
+				continue
+			}
+			lndex[i]--
+			l, found := self.history.Get(lndex)
+			lndex[i]++
+			if !found {
+				panic("the level indices should be downward closed")
+			}
+			self.forward[l*ni+i] = k
+			self.backward[k*ni+i] = l
+		}
+	}
+	for k := uint(0); k < nn; k++ {
+		if len(self.Peek(k)) > 0 {
+			self.Positions[k] = true
+		}
+	}
+}
+
+// Next returns admissible forward neighbors of a level index and activates
+// them.
 func (self *Active) Next(k uint) []uint64 {
+	return self.advance(k, true)
+}
+
+// Peek returns admissible forward neighbors of a level index without
+// activating them.
+func (self *Active) Peek(k uint) []uint64 {
+	return self.advance(k, false)
+}
+
+// Around returns the positions of the level indices whose admissible forward
+// neighbors can change due to the level index at position k, which are its
+// backward neighbors and their forward neighbors.
+func (self *Active) Around(k uint) []uint {
+	ni := self.ni
+	positions := []uint{}
+	for j := uint(0); j < ni; j++ {
+		l, found := self.backward[k*ni+j]
+		if !found {
+			continue
+		}
+		positions = append(positions, l)
+		for i := uint(0); i < ni; i++ {
+			if m, found := self.forward[l*ni+i]; found && m != k {
+				positions = append(positions, m)
+			}
+		}
+	}
+	return positions
+}
+
+func (self *Active) advance(k uint, activate bool) []uint64 {
 	ni := self.ni
 	no := uint(len(self.Lndices)) / ni
 
 	forward, backward := self.forward, self.backward
 	lndex := self.Lndices[k*ni : (k+1)*ni]
+	peeked := []uint64{}
 
 outer:
 	for i, nn := uint(0), no; i < ni; i++ {
 		lndex[i]++
 		_, found := self.history.Get(lndex)
+		admitted := self.limit == nil || self.limit.Admit(lndex, self.lmax)
 		lndex[i]--
 
 		if found {
 			// The forward neighbor in dimension i has already been considered.
 			continue
 		}
+		if !admitted {
+			// The forward neighbor in dimension i exceeds the maximal level.
+			continue
+		}
 
 		newBackward := make(reference)
 		for j := uint(0); j < ni; j++ {
@@ -77,6 +160,13 @@ outer:
 		}
 		newBackward[i] = k
 
+		if !activate {
+			lndex[i]++
+			peeked = append(peeked, lndex...)
+			lndex[i]--
+			continue
+		}
+
 		lndex[i]++
 		self.Lndices = append(self.Lndices, lndex...)
 		self.history.Set(lndex, 0)
@@ -92,5 +182,8 @@ outer:
 		nn++
 	}
 
+	if !activate {
+		return peeked
+	}
 	return self.Lndices[no*ni:]
 }
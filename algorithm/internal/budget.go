@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"time"
+)
+
+// Budget is a limit on the number of nodes and on the running time.
+type Budget struct {
+	nodes    uint
+	duration time.Duration
+
+	consumed uint
+	start    time.Time
+}
+
+// NewBudget creates a Budget. A zero limit means no limit.
+func NewBudget(nodes uint, duration time.Duration) *Budget {
+	return &Budget{
+		nodes:    nodes,
+		duration: duration,
+	}
+}
+
+// Start resets the consumption and starts the clock.
+func (self *Budget) Start() {
+	self.consumed = 0
+	self.start = time.Now()
+}
+
+// Consume takes into account a number of nodes.
+func (self *Budget) Consume(count uint) {
+	self.consumed += count
+}
+
+// Expired checks if the running time has been exhausted.
+func (self *Budget) Expired() bool {
+	return self.duration > 0 && time.Since(self.start) >= self.duration
+}
+
+// Fits checks if a number of nodes fits in the remaining budget.
+func (self *Budget) Fits(count uint) bool {
+	return self.nodes == 0 || self.consumed+count <= self.nodes
+}
+
+// Timed checks if the running time is limited.
+func (self *Budget) Timed() bool {
+	return self.duration > 0
+}
+
+// Limited checks if the number of nodes is limited.
+func (self *Budget) Limited() bool {
+	return self.nodes > 0
+}
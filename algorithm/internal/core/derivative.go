@@ -0,0 +1,54 @@
+package core
+
+import (
+	"github.com/ready-steady/adapt/algorithm"
+	"github.com/ready-steady/adapt/algorithm/internal"
+)
+
+// Gradient computes the gradients of an interpolant at a set of points using an
+// estimator of the interpolant. For each point, the result contains the
+// Jacobian matrix of the outputs with respect to the inputs, which is an
+// outputs-by-inputs matrix in row-major order.
+func Gradient(estimator internal.Estimator, surrogate *algorithm.Surrogate,
+	points []float64) []float64 {
+
+	return differentiate(estimator.Gradient, surrogate, points, 1)
+}
+
+// Hessian computes the Hessian matrices of an interpolant at a set of points
+// using an estimator of the interpolant. For each point, the result contains an
+// outputs-by-inputs-by-inputs array in row-major order, which is composed of
+// the Hessian matrices of the outputs.
+func Hessian(estimator internal.Estimator, surrogate *algorithm.Surrogate,
+	points []float64) []float64 {
+
+	return differentiate(estimator.Hessian, surrogate, points, 2)
+}
+
+func differentiate(derive func([]float64, []float64) []float64,
+	surrogate *algorithm.Surrogate, points []float64, order uint) []float64 {
+
+	domain := surrogate.Domain
+	if domain != nil {
+		points = domain.Backward(points)
+	}
+	values := derive(surrogate.Surpluses, points)
+	if domain == nil {
+		return values
+	}
+
+	ni := surrogate.Inputs
+	nd := ni
+	if order == 2 {
+		nd *= ni
+	}
+	scale := domain.Jacobian()
+	for i := range values {
+		l := uint(i) % nd
+		values[i] *= scale[l%ni]
+		if order == 2 {
+			values[i] *= scale[l/ni]
+		}
+	}
+	return values
+}
@@ -0,0 +1,34 @@
+// Package core contains code shared by the interpolation algorithms.
+package core
+
+import (
+	"github.com/ready-steady/adapt/algorithm"
+	"github.com/ready-steady/adapt/algorithm/internal"
+	"github.com/ready-steady/adapt/basis"
+	"github.com/ready-steady/adapt/grid"
+)
+
+// Basis is an interpolation basis.
+type Basis interface {
+	basis.Computer
+	basis.Integrator
+}
+
+// Config is the configuration of an interpolation algorithm.
+type Config struct {
+	Inputs  uint
+	Outputs uint
+
+	Grid   grid.Computer
+	Basis  Basis
+	Domain *algorithm.Domain
+
+	Observer algorithm.Observer
+	Record   bool
+
+	// Plant creates an estimator for evaluating interpolants.
+	Plant func() internal.Estimator
+
+	// Score assigns scores to the nodes of a state.
+	Score func(algorithm.Strategy, *algorithm.State, uint, uint) []float64
+}
@@ -0,0 +1,204 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ready-steady/adapt/algorithm"
+	"github.com/ready-steady/adapt/algorithm/internal"
+)
+
+// Process is a step-wise interpolation process.
+type Process struct {
+	config    Config
+	strategy  algorithm.Strategy
+	surrogate *algorithm.Surrogate
+	estimator internal.Estimator
+
+	state    *algorithm.State
+	received []bool
+	pending  uint
+
+	iteration uint
+	ready     time.Time
+	spent     time.Duration
+}
+
+// Start begins a step-wise interpolation process.
+func Start(config Config, strategy algorithm.Strategy) *Process {
+	surrogate := algorithm.NewSurrogate(config.Inputs, config.Outputs)
+	surrogate.Domain = config.Domain
+	process := &Process{
+		config:    config,
+		strategy:  strategy,
+		surrogate: surrogate,
+		estimator: config.Plant(),
+	}
+	process.prepare(strategy.First(surrogate))
+	return process
+}
+
+// Compute drives a process to completion by invoking a target.
+func Compute(process *Process, target algorithm.Target) *algorithm.Surrogate {
+	ni, no := process.config.Inputs, process.config.Outputs
+	for !process.Done() {
+		positions, nodes := process.Ask()
+		if err := process.Tell(positions, algorithm.Invoke(target, nodes, ni, no)); err != nil {
+			panic(err)
+		}
+	}
+	return process.Surrogate()
+}
+
+// ComputeContext drives a process to completion by invoking a target that can
+// fail and be cancelled.
+func ComputeContext(ctx context.Context, process *Process,
+	target algorithm.ContextTarget) (*algorithm.Surrogate, error) {
+
+	ni, no := process.config.Inputs, process.config.Outputs
+	for !process.Done() {
+		positions, nodes := process.Ask()
+		values, err := algorithm.InvokeContext(ctx, target, nodes, ni, no)
+		if err != nil {
+			return process.Surrogate(), err
+		}
+		if err = process.Tell(positions, values); err != nil {
+			return process.Surrogate(), err
+		}
+	}
+	return process.Surrogate(), nil
+}
+
+// Ask returns the positions and nodes of the current state whose values have
+// not been supplied yet.
+func (self *Process) Ask() ([]uint, []float64) {
+	if self.state == nil {
+		return nil, nil
+	}
+	ni := self.config.Inputs
+	positions, nodes := []uint{}, []float64{}
+	for i, received := range self.received {
+		if !received {
+			positions = append(positions, uint(i))
+			nodes = append(nodes, self.state.Nodes[uint(i)*ni:uint(i+1)*ni]...)
+		}
+	}
+	return positions, nodes
+}
+
+// Tell supplies the values of the target at a set of nodes of the current
+// state identified by their positions. Once all the values of the current state
+// have been supplied, the process advances to the next iteration.
+func (self *Process) Tell(positions []uint, values []float64) error {
+	no := self.config.Outputs
+	if self.state == nil {
+		return errors.New("the process is done")
+	}
+	if uint(len(values)) != uint(len(positions))*no {
+		return errors.New("the number of values is inconsistent")
+	}
+	supplied := make(map[uint]bool, len(positions))
+	for _, i := range positions {
+		if i >= uint(len(self.received)) {
+			return errors.New("the position is out of range")
+		}
+		if self.received[i] || supplied[i] {
+			return errors.New("the value has already been supplied")
+		}
+		supplied[i] = true
+	}
+	for k, i := range positions {
+		copy(self.state.Values[i*no:(i+1)*no], values[uint(k)*no:uint(k+1)*no])
+		self.received[i] = true
+		self.pending--
+	}
+	if self.pending == 0 {
+		self.advance()
+	}
+	return nil
+}
+
+// Done checks if the process has finished.
+func (self *Process) Done() bool {
+	return self.state == nil
+}
+
+// State returns the current state.
+func (self *Process) State() *algorithm.State {
+	return self.state
+}
+
+// Strategy returns the strategy controlling the process.
+func (self *Process) Strategy() algorithm.Strategy {
+	return self.strategy
+}
+
+// Estimator returns the estimator of the interpolant constructed so far.
+func (self *Process) Estimator() internal.Estimator {
+	return self.estimator
+}
+
+// Surrogate returns the interpolant constructed so far.
+func (self *Process) Surrogate() *algorithm.Surrogate {
+	return self.surrogate
+}
+
+func (self *Process) advance() {
+	ni, no := self.config.Inputs, self.config.Outputs
+	s := self.state
+	start := time.Now()
+	evaluation := start.Sub(self.ready)
+	s.Surpluses = internal.Subtract(s.Values, s.Estimates)
+	s.Scores = self.config.Score(self.strategy, s, ni, no)
+	self.surrogate.Push(s.Indices, s.Surpluses, s.Volumes)
+	self.estimator.Push(s.Indices)
+	estimation := self.spent + time.Since(start)
+	next := self.strategy.Next(s, self.surrogate)
+	if self.config.Observer != nil || self.config.Record {
+		progress := &algorithm.Progress{
+			Iteration:  self.iteration,
+			Nodes:      uint(len(s.Indices)) / ni,
+			Total:      self.surrogate.Nodes,
+			Evaluation: evaluation,
+			Estimation: estimation,
+			State:      s,
+		}
+		if reporter, ok := self.strategy.(algorithm.Reporter); ok {
+			reporter.Report(progress)
+		}
+		if self.config.Record {
+			self.surrogate.Track(s.Surpluses, progress.Active)
+		}
+		if observer := self.config.Observer; observer != nil {
+			observer.Observe(progress)
+		}
+	}
+	self.iteration++
+	self.prepare(next)
+}
+
+func (self *Process) prepare(s *algorithm.State) {
+	self.state = s
+	if s == nil {
+		return
+	}
+	ni, no := self.config.Inputs, self.config.Outputs
+	nn := uint(len(s.Indices)) / ni
+	start := time.Now()
+	s.Volumes = internal.Measure(self.config.Basis, s.Indices, ni)
+	s.Nodes = self.config.Grid.Compute(s.Indices)
+	s.Estimates = self.estimator.Estimate(self.surrogate.Surpluses, s.Nodes)
+	if domain := self.config.Domain; domain != nil {
+		internal.Scale(s.Volumes, domain.Volume())
+		s.Nodes = domain.Forward(s.Nodes)
+	}
+	s.Values = make([]float64, nn*no)
+	self.ready = time.Now()
+	self.spent = self.ready.Sub(start)
+	self.received = make([]bool, nn)
+	self.pending = nn
+	if nn == 0 {
+		self.advance()
+	}
+}
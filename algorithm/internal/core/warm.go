@@ -0,0 +1,226 @@
+package core
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/ready-steady/adapt/algorithm"
+	"github.com/ready-steady/adapt/algorithm/internal"
+
+	rinternal "github.com/ready-steady/adapt/internal"
+)
+
+// Refine constructs an interpolant for a function starting from a previously
+// computed one. The values of the function at the nodes of the previous
+// interpolant are taken from the values argument, which is ordered as the
+// nodes, or, if it is nil, from the previous interpolant itself.
+//
+// If the strategy implements algorithm.Resumer, the process continues from the
+// previous interpolant; see Resume. Otherwise, the process is repeated using
+// the known values, which reconstructs the state of the strategy. In both
+// cases, the function is invoked only at the nodes that are new.
+func Refine(config Config, target algorithm.Target, strategy algorithm.Strategy,
+	surrogate *algorithm.Surrogate, values []float64) *algorithm.Surrogate {
+
+	ni, no := config.Inputs, config.Outputs
+	if surrogate.Inputs != ni || surrogate.Outputs != no {
+		panic("the surrogate does not match the algorithm")
+	}
+	if !reflect.DeepEqual(surrogate.Domain, config.Domain) {
+		panic("the domain of the surrogate does not match the one of the algorithm")
+	}
+	if _, ok := strategy.(algorithm.Resumer); ok {
+		return Compute(Resume(config, strategy, surrogate, values), target)
+	}
+	if values == nil {
+		values = evaluate(config, surrogate)
+	}
+	if uint(len(values)) != surrogate.Nodes*no {
+		panic("the number of values does not match the number of nodes")
+	}
+
+	known := internal.NewKnown(surrogate.Indices, values, ni, no)
+	process := Start(config, strategy)
+	for !process.Done() {
+		positions, nodes := process.Ask()
+		state := process.State()
+		cached, fresh := []uint{}, []uint{}
+		cachedValues, freshNodes := []float64{}, []float64{}
+		for k, i := range positions {
+			if value := known.Lookup(state.Indices[i*ni : (i+1)*ni]); value != nil {
+				cached = append(cached, i)
+				cachedValues = append(cachedValues, value...)
+			} else {
+				fresh = append(fresh, i)
+				freshNodes = append(freshNodes, nodes[uint(k)*ni:uint(k+1)*ni]...)
+			}
+		}
+		if err := process.Tell(cached, cachedValues); err != nil {
+			panic(err)
+		}
+		if len(fresh) > 0 {
+			if err := process.Tell(fresh, algorithm.Invoke(target, freshNodes, ni, no)); err != nil {
+				panic(err)
+			}
+		}
+	}
+	return process.Surrogate()
+}
+
+// Resume begins a step-wise interpolation process that continues from a
+// previously computed interpolant given the values of the target at its nodes,
+// which are ordered as the nodes, or, if they are nil, taken from the
+// interpolant itself. The nodes are grouped by level index, and the set of
+// level indices is completed to be downward closed, possibly with empty level
+// indices. The resulting state, in which Data contains the nodal indices of
+// each level index, is passed to the strategy, and the interpolant is rebuilt
+// in the same order. If the values are given, the surpluses are recomputed
+// from them level index by level index; otherwise, they are kept.
+func Resume(config Config, strategy algorithm.Strategy, surrogate *algorithm.Surrogate,
+	values []float64) *Process {
+
+	resumer, ok := strategy.(algorithm.Resumer)
+	if !ok {
+		panic("the strategy does not support resumption")
+	}
+	ni, no := config.Inputs, config.Outputs
+	fresh := values != nil
+	if !fresh {
+		values = evaluate(config, surrogate)
+	}
+	if uint(len(values)) != surrogate.Nodes*no {
+		panic("the number of values does not match the number of nodes")
+	}
+	s := group(surrogate, values, ni, no)
+
+	s.Volumes = internal.Measure(config.Basis, s.Indices, ni)
+	s.Nodes = config.Grid.Compute(s.Indices)
+	if fresh {
+		s.Surpluses = hierarchize(config, s)
+	}
+	if domain := config.Domain; domain != nil {
+		internal.Scale(s.Volumes, domain.Volume())
+		s.Nodes = domain.Forward(s.Nodes)
+	}
+	s.Estimates = internal.Subtract(s.Values, s.Surpluses)
+	s.Scores = config.Score(strategy, s, ni, no)
+
+	resumed := algorithm.NewSurrogate(ni, no)
+	resumed.Domain = config.Domain
+	resumed.Push(s.Indices, s.Surpluses, s.Volumes)
+
+	process := &Process{
+		config:    config,
+		strategy:  strategy,
+		surrogate: resumed,
+		estimator: config.Plant(),
+	}
+	process.estimator.Push(s.Indices)
+	process.prepare(resumer.Resume(s, resumed))
+	return process
+}
+
+// evaluate computes the values of an interpolant at its nodes.
+func evaluate(config Config, surrogate *algorithm.Surrogate) []float64 {
+	estimator := config.Plant()
+	estimator.Push(surrogate.Indices)
+	return estimator.Estimate(surrogate.Surpluses, config.Grid.Compute(surrogate.Indices))
+}
+
+// hierarchize computes the surpluses of a state whose nodes, which are given
+// in the unit hypercube, are grouped by level index in an order in which the
+// interpolation process could have added them.
+func hierarchize(config Config, s *algorithm.State) []float64 {
+	ni, no := config.Inputs, config.Outputs
+	estimator := config.Plant()
+	surpluses := make([]float64, len(s.Values))
+	for i, o := 0, uint(0); i < len(s.Counts); i++ {
+		m := o + s.Counts[i]
+		estimates := estimator.Estimate(surpluses[:o*no], s.Nodes[o*ni:m*ni])
+		for j := o * no; j < m*no; j++ {
+			surpluses[j] = s.Values[j] - estimates[j-o*no]
+		}
+		estimator.Push(s.Indices[o*ni : m*ni])
+		o = m
+	}
+	return surpluses
+}
+
+func group(surrogate *algorithm.Surrogate, values []float64, ni, no uint) *algorithm.State {
+	nn := surrogate.Nodes
+
+	history := internal.NewHistory(ni)
+	lndices, members := []uint64{}, [][]uint{}
+	lndex := make([]uint64, ni)
+	visit := func(lndex []uint64) uint {
+		k, found := history.GetSet(lndex, uint(len(members)))
+		if !found {
+			k = uint(len(members))
+			lndices = append(lndices, lndex...)
+			members = append(members, nil)
+		}
+		return k
+	}
+	for i := uint(0); i < nn; i++ {
+		for j := uint(0); j < ni; j++ {
+			lndex[j] = rinternal.LEVEL_MASK & surrogate.Indices[i*ni+j]
+		}
+		k := visit(lndex)
+		members[k] = append(members[k], i)
+	}
+	for k := uint(0); k < uint(len(members)); k++ {
+		copy(lndex, lndices[k*ni:(k+1)*ni])
+		for j := uint(0); j < ni; j++ {
+			if lndex[j] == 0 {
+				continue
+			}
+			lndex[j]--
+			visit(lndex)
+			lndex[j]++
+		}
+	}
+
+	nl := uint(len(members))
+	order := make([]uint, nl)
+	norms := make([]uint64, nl)
+	for k := uint(0); k < nl; k++ {
+		order[k] = k
+		for j := uint(0); j < ni; j++ {
+			norms[k] += lndices[k*ni+j]
+		}
+	}
+	sort.Slice(order, func(i, j int) bool {
+		k, l := order[i], order[j]
+		if norms[k] != norms[l] {
+			return norms[k] < norms[l]
+		}
+		for m := uint(0); m < ni; m++ {
+			if lndices[k*ni+m] != lndices[l*ni+m] {
+				return lndices[k*ni+m] < lndices[l*ni+m]
+			}
+		}
+		return false
+	})
+
+	s := &algorithm.State{
+		Lndices:   make([]uint64, 0, nl*ni),
+		Indices:   make([]uint64, 0, nn*ni),
+		Counts:    make([]uint, nl),
+		Values:    make([]float64, 0, nn*no),
+		Surpluses: make([]float64, 0, nn*no),
+	}
+	groups := make([][]uint64, nl)
+	for o, k := range order {
+		s.Lndices = append(s.Lndices, lndices[k*ni:(k+1)*ni]...)
+		s.Counts[o] = uint(len(members[k]))
+		for _, i := range members[k] {
+			index := surrogate.Indices[i*ni : (i+1)*ni]
+			groups[o] = append(groups[o], index...)
+			s.Indices = append(s.Indices, index...)
+			s.Values = append(s.Values, values[i*no:(i+1)*no]...)
+			s.Surpluses = append(s.Surpluses, surrogate.Surpluses[i*no:(i+1)*no]...)
+		}
+	}
+	s.Data = groups
+	return s
+}
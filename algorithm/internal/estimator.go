@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"github.com/ready-steady/adapt/basis"
+)
+
+// Estimator evaluates interpolants whose nodal indices are supplied
+// incrementally.
+type Estimator interface {
+	// Push takes into account new indices.
+	Push([]uint64)
+
+	// Estimate evaluates an interpolant at multiple points.
+	Estimate([]float64, []float64) []float64
+
+	// Gradient evaluates the gradients of an interpolant at multiple points.
+	Gradient([]float64, []float64) []float64
+
+	// Hessian evaluates the Hessian matrices of an interpolant at multiple
+	// points.
+	Hessian([]float64, []float64) []float64
+}
+
+// Scan is an Estimator that evaluates all basis functions at each point.
+type Scan struct {
+	ni uint
+	no uint
+
+	computer basis.Computer
+	indices  []uint64
+}
+
+// NewScan creates a Scan.
+func NewScan(computer basis.Computer, ni, no uint) *Scan {
+	return &Scan{
+		ni: ni,
+		no: no,
+
+		computer: computer,
+	}
+}
+
+// Push takes into account new indices.
+func (self *Scan) Push(indices []uint64) {
+	self.indices = append(self.indices, indices...)
+}
+
+// Estimate evaluates an interpolant at multiple points using multiple
+// goroutines; see the function with the same name.
+func (self *Scan) Estimate(surpluses, points []float64) []float64 {
+	return Estimate(self.computer, self.indices, surpluses, points, self.ni, self.no)
+}
+
+// Gradient evaluates the gradients of an interpolant at multiple points using
+// multiple goroutines; see the function with the same name.
+func (self *Scan) Gradient(surpluses, points []float64) []float64 {
+	return Gradient(differentiator(self.computer), self.indices, surpluses, points,
+		self.ni, self.no)
+}
+
+// Hessian evaluates the Hessian matrices of an interpolant at multiple points
+// using multiple goroutines; see the function with the same name.
+func (self *Scan) Hessian(surpluses, points []float64) []float64 {
+	return Hessian(differentiator(self.computer), self.indices, surpluses, points,
+		self.ni, self.no)
+}
+
+func differentiator(computer basis.Computer) basis.Differentiator {
+	differentiator, ok := computer.(basis.Differentiator)
+	if !ok {
+		panic("the basis does not support differentiation")
+	}
+	return differentiator
+}
@@ -0,0 +1,27 @@
+package internal
+
+// Known is a structure for keeping track of known values at nodal indices.
+type Known struct {
+	*History
+	values []float64
+	no     uint
+}
+
+// NewKnown creates a Known.
+func NewKnown(indices []uint64, values []float64, ni, no uint) *Known {
+	history := NewHistory(ni)
+	nn := uint(len(indices)) / ni
+	for i := uint(0); i < nn; i++ {
+		history.Set(indices[i*ni:(i+1)*ni], i)
+	}
+	return &Known{History: history, values: values, no: no}
+}
+
+// Lookup returns the values at a nodal index or nil if they are unknown.
+func (self *Known) Lookup(index []uint64) []float64 {
+	i, found := self.Get(index)
+	if !found {
+		return nil
+	}
+	return self.values[i*self.no : (i+1)*self.no]
+}
@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"github.com/ready-steady/adapt/internal"
+)
+
+// Limit is a set of constraints on the levels of indices. The minimal and
+// maximal level norms are passed to the methods that need them.
+type Limit struct {
+	ni uint
+
+	lower   []uint64
+	upper   []uint64
+	weights []float64
+}
+
+// NewLimit creates a Limit.
+func NewLimit(ni uint) *Limit {
+	return &Limit{
+		ni: ni,
+	}
+}
+
+// Bound sets the minimal and maximal levels of each dimension.
+func (self *Limit) Bound(lower, upper []uint) {
+	if uint(len(lower)) != self.ni || uint(len(upper)) != self.ni {
+		panic("the number of levels should be equal to the number of inputs")
+	}
+	self.lower, self.upper = make([]uint64, self.ni), make([]uint64, self.ni)
+	for i := uint(0); i < self.ni; i++ {
+		self.lower[i], self.upper[i] = uint64(lower[i]), uint64(upper[i])
+	}
+}
+
+// Weigh sets the weights of the dimensions in the level norm.
+func (self *Limit) Weigh(weights []float64) {
+	if uint(len(weights)) != self.ni {
+		panic("the number of weights should be equal to the number of inputs")
+	}
+	self.weights = append([]float64(nil), weights...)
+}
+
+// Admit checks if an index respects the maximal level norm and the maximal
+// levels of the dimensions.
+func (self *Limit) Admit(index []uint64, lmax uint) bool {
+	if self.norm(index) > float64(lmax) {
+		return false
+	}
+	if self.upper == nil {
+		return true
+	}
+	for i, level := range index {
+		if internal.LEVEL_MASK&level > self.upper[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Below checks if an index is below the minimal level norm or the minimal
+// levels of the dimensions, in which case it should be refined regardless of
+// its score. An index is below the minimal levels of the dimensions if it does
+// not exceed them in any dimension and falls short of them in at least one, so
+// that only the indices leading to the minimal levels are forced.
+func (self *Limit) Below(index []uint64, lmin uint) bool {
+	if self.norm(index) < float64(lmin) {
+		return true
+	}
+	if self.lower == nil {
+		return false
+	}
+	below := false
+	for i, level := range index {
+		level &= internal.LEVEL_MASK
+		if level > self.lower[i] {
+			return false
+		}
+		if level < self.lower[i] {
+			below = true
+		}
+	}
+	return below
+}
+
+// Filter eliminates the indices that are not admitted.
+func (self *Limit) Filter(indices []uint64, lmax uint) []uint64 {
+	ni := self.ni
+	nn := uint(len(indices)) / ni
+	k, admitted := uint(0), []uint64{}
+	for i := uint(0); i < nn; i++ {
+		if !self.Admit(indices[i*ni:(i+1)*ni], lmax) {
+			admitted = append(admitted, indices[k*ni:i*ni]...)
+			k = i + 1
+		}
+	}
+	if k == 0 {
+		return indices
+	}
+	return append(admitted, indices[k*ni:]...)
+}
+
+// Reached checks if an index has reached the maximal level norm, in which case
+// it should not be refined.
+func (self *Limit) Reached(index []uint64, lmax uint) bool {
+	return self.norm(index) >= float64(lmax)
+}
+
+func (self *Limit) norm(index []uint64) float64 {
+	if self.weights == nil {
+		sum := uint64(0)
+		for _, level := range index {
+			sum += internal.LEVEL_MASK & level
+		}
+		return float64(sum)
+	}
+	sum := 0.0
+	for i, level := range index {
+		sum += self.weights[i] * float64(internal.LEVEL_MASK&level)
+	}
+	return sum
+}
@@ -58,6 +58,124 @@ func Estimate(computer basis.Computer, indices []uint64, surpluses,
 	return values
 }
 
+// Project multiplies a set of values given at multiple points by the basis
+// functions evaluated at the points and sums the products for each basis
+// function using multiple goroutines, which is the transpose of Estimate.
+func Project(computer basis.Computer, indices []uint64, values,
+	points []float64, ni, no uint) []float64 {
+
+	nn := uint(len(indices)) / ni
+	np := uint(len(points)) / ni
+	result := make([]float64, nn*no)
+
+	jobs := make(chan uint, nn)
+	group := sync.WaitGroup{}
+	group.Add(int(nn))
+
+	for i := uint(0); i < Workers; i++ {
+		go func() {
+			for j := range jobs {
+				index := indices[j*ni : (j+1)*ni]
+				sum := result[j*no : (j+1)*no]
+
+				for k := uint(0); k < np; k++ {
+					weight := computer.Compute(index, points[k*ni:(k+1)*ni])
+					if weight == 0.0 {
+						continue
+					}
+					for l := uint(0); l < no; l++ {
+						sum[l] += weight * values[k*no+l]
+					}
+				}
+
+				group.Done()
+			}
+		}()
+	}
+
+	for i := uint(0); i < nn; i++ {
+		jobs <- i
+	}
+
+	group.Wait()
+	close(jobs)
+
+	return result
+}
+
+// Gradient evaluates the gradients of an interpolant at multiple points using
+// multiple goroutines. For each point, the result contains the Jacobian matrix
+// of the outputs with respect to the inputs, which is an no-by-ni matrix in
+// row-major order.
+func Gradient(differentiator basis.Differentiator, indices []uint64, surpluses,
+	points []float64, ni, no uint) []float64 {
+
+	return derive(indices, surpluses, points, ni, no, 1,
+		func(index []uint64, point []float64, i, _ uint) float64 {
+			return differentiator.Differentiate(index, point, i)
+		})
+}
+
+// Hessian evaluates the Hessian matrices of an interpolant at multiple points
+// using multiple goroutines. For each point, the result contains an
+// no-by-ni-by-ni array in row-major order, which is composed of the Hessian
+// matrices of the outputs.
+func Hessian(differentiator basis.Differentiator, indices []uint64, surpluses,
+	points []float64, ni, no uint) []float64 {
+
+	return derive(indices, surpluses, points, ni, no, 2,
+		differentiator.DifferentiateTwice)
+}
+
+func derive(indices []uint64, surpluses, points []float64, ni, no, order uint,
+	compute func([]uint64, []float64, uint, uint) float64) []float64 {
+
+	nn := uint(len(indices)) / ni
+	np := uint(len(points)) / ni
+	nd := ni
+	if order == 2 {
+		nd *= ni
+	}
+	values := make([]float64, np*no*nd)
+
+	jobs := make(chan uint, np)
+	group := sync.WaitGroup{}
+	group.Add(int(np))
+
+	for i := uint(0); i < Workers; i++ {
+		go func() {
+			for j := range jobs {
+				point := points[j*ni : (j+1)*ni]
+				value := values[j*no*nd : (j+1)*no*nd]
+
+				for k := uint(0); k < nn; k++ {
+					index := indices[k*ni : (k+1)*ni]
+					for l := uint(0); l < nd; l++ {
+						weight := compute(index, point, l%ni, l/ni)
+						if weight == 0.0 {
+							continue
+						}
+						for m := uint(0); m < no; m++ {
+							value[m*nd+l] += weight * surpluses[k*no+m]
+						}
+					}
+				}
+
+				group.Done()
+			}
+		}()
+	}
+
+	for i := uint(0); i < np; i++ {
+		jobs <- i
+	}
+
+	group.Wait()
+	close(jobs)
+
+	return values
+}
+
 // Index returns the nodal indices of a set of level indices.
 func Index(indexer grid.Indexer, lndices []uint64, ni uint) ([]uint64, []uint) {
 	nn := uint(len(lndices)) / ni
@@ -0,0 +1,81 @@
+package internal
+
+// Solve solves a set of symmetric positive-definite linear systems with a
+// common matrix using the conjugate gradient method. The matrix and the
+// preconditioner are given as functions multiplying them by a vector, the
+// right-hand sides are stored as the columns of an nn-by-no matrix in row-major
+// order, and so are the initial solutions, which are overwritten with the final
+// ones. The iterations stop for a system when the norm of its residual drops
+// below the tolerance relative to the norm of its right-hand side.
+func Solve(multiply, precondition func([]float64) []float64, b, x []float64,
+	nn, no, iterations uint, tolerance float64) []float64 {
+
+	r := multiply(x)
+	for i := range r {
+		r[i] = b[i] - r[i]
+	}
+	z := precondition(r)
+	p := append([]float64(nil), z...)
+
+	ρ, ε := dot(r, z, nn, no), dot(b, b, nn, no)
+	for j := uint(0); j < no; j++ {
+		ε[j] *= tolerance * tolerance
+	}
+
+	active := make([]bool, no)
+	α, β := make([]float64, no), make([]float64, no)
+	for k := uint(0); k < iterations; k++ {
+		done, δ := true, dot(r, r, nn, no)
+		for j := uint(0); j < no; j++ {
+			active[j] = δ[j] > ε[j]
+			done = done && !active[j]
+		}
+		if done {
+			break
+		}
+
+		q := multiply(p)
+		γ := dot(p, q, nn, no)
+		for j := uint(0); j < no; j++ {
+			if active[j] && γ[j] > 0.0 {
+				α[j] = ρ[j] / γ[j]
+			} else {
+				α[j] = 0.0
+			}
+		}
+		for i := uint(0); i < nn; i++ {
+			for j := uint(0); j < no; j++ {
+				x[i*no+j] += α[j] * p[i*no+j]
+				r[i*no+j] -= α[j] * q[i*no+j]
+			}
+		}
+
+		z = precondition(r)
+		σ := dot(r, z, nn, no)
+		for j := uint(0); j < no; j++ {
+			if α[j] == 0.0 {
+				β[j] = 0.0
+			} else {
+				β[j] = σ[j] / ρ[j]
+			}
+			ρ[j] = σ[j]
+		}
+		for i := uint(0); i < nn; i++ {
+			for j := uint(0); j < no; j++ {
+				p[i*no+j] = z[i*no+j] + β[j]*p[i*no+j]
+			}
+		}
+	}
+
+	return x
+}
+
+func dot(x, y []float64, nn, no uint) []float64 {
+	result := make([]float64, no)
+	for i := uint(0); i < nn; i++ {
+		for j := uint(0); j < no; j++ {
+			result[j] += x[i*no+j] * y[i*no+j]
+		}
+	}
+	return result
+}
@@ -0,0 +1,39 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestSolve(t *testing.T) {
+	const (
+		nn = 3
+		no = 2
+	)
+
+	matrix := []float64{
+		4.0, 1.0, 0.0,
+		1.0, 3.0, 1.0,
+		0.0, 1.0, 2.0,
+	}
+	multiply := func(x []float64) []float64 {
+		y := make([]float64, nn*no)
+		for i := 0; i < nn; i++ {
+			for j := 0; j < nn; j++ {
+				for k := 0; k < no; k++ {
+					y[i*no+k] += matrix[i*nn+j] * x[j*no+k]
+				}
+			}
+		}
+		return y
+	}
+	precondition := func(x []float64) []float64 {
+		return append([]float64(nil), x...)
+	}
+
+	expected := []float64{1.0, -1.0, 2.0, 0.0, 3.0, 1.0}
+	b := multiply(expected)
+	x := Solve(multiply, precondition, b, make([]float64, nn*no), nn, no, 10, 1e-12)
+	assert.Close(x, expected, 1e-10, t)
+}
@@ -65,6 +65,13 @@ func MaxAbsolute(data []float64) (result float64) {
 	return
 }
 
+// Scale multiplies a vector by a fixed value.
+func Scale(data []float64, value float64) {
+	for i := range data {
+		data[i] *= value
+	}
+}
+
 // Set overwrites a vector with a fixed value.
 func Set(data []float64, value float64) {
 	for i := range data {
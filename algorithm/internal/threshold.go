@@ -11,8 +11,8 @@ type Threshold struct {
 	upper  []float64
 
 	no uint
-	εa float64
-	εr float64
+	εa []float64
+	εr []float64
 }
 
 // NewThreshold creates a Threshold.
@@ -23,11 +23,20 @@ func NewThreshold(outputs uint, absolute, relative float64) *Threshold {
 		upper:  repeat(-Infinity, outputs),
 
 		no: outputs,
-		εa: absolute,
-		εr: relative,
+		εa: repeat(absolute, outputs),
+		εr: repeat(relative, outputs),
 	}
 }
 
+// Tolerate sets the absolute and relative errors of each output.
+func (self *Threshold) Tolerate(absolute, relative []float64) {
+	if uint(len(absolute)) != self.no || uint(len(relative)) != self.no {
+		panic("the number of errors should be equal to the number of outputs")
+	}
+	self.εa = append([]float64(nil), absolute...)
+	self.εr = append([]float64(nil), relative...)
+}
+
 // Check checks if the threshold is satisfied.
 func (self *Threshold) Check(errors []float64, include map[uint]bool) bool {
 	no := self.no
@@ -41,6 +50,11 @@ func (self *Threshold) Check(errors []float64, include map[uint]bool) bool {
 	return true
 }
 
+// Values returns the current values of the threshold.
+func (self *Threshold) Values() []float64 {
+	return append([]float64(nil), self.values...)
+}
+
 // Compress compresses multiple errors into a single one so that it can later on
 // be tested against the threshold.
 func (self *Threshold) Compress(error, errors []float64) {
@@ -59,7 +73,7 @@ func (self *Threshold) Update(values []float64) {
 		self.upper[j] = math.Max(self.upper[j], values[i])
 	}
 	for i := uint(0); i < no; i++ {
-		self.values[i] = math.Max(self.εa, self.εr*(self.upper[i]-self.lower[i]))
+		self.values[i] = math.Max(self.εa[i], self.εr[i]*(self.upper[i]-self.lower[i]))
 	}
 }
 
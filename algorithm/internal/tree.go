@@ -0,0 +1,347 @@
+package internal
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/ready-steady/adapt/basis"
+	"github.com/ready-steady/adapt/grid"
+	"github.com/ready-steady/adapt/internal"
+)
+
+// Tree is a structure for evaluating interpolants with local bases.
+//
+// The nodal indices are organized in a trie with one layer per dimension. In
+// order to evaluate an interpolant at a point, the one-dimensional hierarchy of
+// each dimension is descended toward the point, following only the children
+// whose basis functions are nonzero at the point, and the trie is then
+// traversed along the resulting chains. The basis functions of level zero are
+// assumed to be equal to one, and the support of each basis function is
+// assumed to contain the supports of its children, which is the case for local
+// bases. The cost per point is then proportional to the number of basis
+// functions whose supports contain the point.
+type Tree struct {
+	ni uint
+	no uint
+	nn uint
+
+	refiner  grid.RefinerToward
+	computer basis.Computer
+
+	root   *branch
+	levels []uint64
+}
+
+type branch struct {
+	children map[uint64]*branch
+	position uint
+}
+
+type link struct {
+	index uint64
+	value float64
+}
+
+type term struct {
+	position uint
+	weight   float64
+}
+
+type jet struct {
+	index  uint64
+	values [3]float64
+}
+
+// NewTree creates a Tree.
+func NewTree(refiner grid.RefinerToward, computer basis.Computer, ni, no uint) *Tree {
+	return &Tree{
+		ni: ni,
+		no: no,
+
+		refiner:  refiner,
+		computer: computer,
+
+		root:   &branch{children: make(map[uint64]*branch)},
+		levels: make([]uint64, ni),
+	}
+}
+
+// Push takes into account new indices.
+func (self *Tree) Push(indices []uint64) {
+	ni := self.ni
+	nn := uint(len(indices)) / ni
+	for i := uint(0); i < nn; i++ {
+		current := self.root
+		for j := uint(0); j < ni; j++ {
+			index := indices[i*ni+j]
+			if level := index & internal.LEVEL_MASK; level > self.levels[j] {
+				self.levels[j] = level
+			}
+			child, ok := current.children[index]
+			if !ok {
+				child = &branch{}
+				if j+1 < ni {
+					child.children = make(map[uint64]*branch)
+				}
+				current.children[index] = child
+			}
+			current = child
+		}
+		current.position = self.nn
+		self.nn++
+	}
+}
+
+// Estimate evaluates an interpolant at multiple points using multiple
+// goroutines. The result is identical to the one of the function with the
+// same name.
+func (self *Tree) Estimate(surpluses, points []float64) []float64 {
+	ni, no := self.ni, self.no
+	np := uint(len(points)) / ni
+	values := make([]float64, np*no)
+	if self.nn == 0 {
+		return values
+	}
+
+	jobs := make(chan uint, np)
+	group := sync.WaitGroup{}
+	group.Add(int(np))
+
+	for i := uint(0); i < Workers; i++ {
+		go func() {
+			index := make([]uint64, ni)
+			chains := make([][]link, ni)
+			terms := []term{}
+
+			for j := range jobs {
+				point := points[j*ni : (j+1)*ni]
+				value := values[j*no : (j+1)*no]
+
+				for k := uint(0); k < ni; k++ {
+					chains[k] = self.descend(chains[k][:0], index, point, k)
+				}
+				terms = self.traverse(terms[:0], self.root, chains, 0, 1.0)
+
+				// Accumulate in the order of the nodes in order to obtain the
+				// same result as the one of a full scan.
+				sort.Sort(byPosition(terms))
+				for _, term := range terms {
+					for l := uint(0); l < no; l++ {
+						value[l] += term.weight * surpluses[term.position*no+l]
+					}
+				}
+
+				group.Done()
+			}
+		}()
+	}
+
+	for i := uint(0); i < np; i++ {
+		jobs <- i
+	}
+
+	group.Wait()
+	close(jobs)
+
+	return values
+}
+
+// descend finds the one-dimensional basis functions of a dimension that are
+// nonzero at a point. The index should be zero in all dimensions.
+func (self *Tree) descend(chain []link, index []uint64, point []float64, i uint) []link {
+	queue := []uint64{0}
+	for len(queue) > 0 {
+		index[i] = queue[0]
+		queue = queue[1:]
+
+		value := self.computer.Compute(index, point)
+		if value == 0.0 {
+			continue
+		}
+		chain = append(chain, link{index: index[i], value: value})
+
+		if index[i]&internal.LEVEL_MASK >= self.levels[i] {
+			continue
+		}
+		children := self.refiner.RefineToward(index, i)
+		for j, m := uint(0), uint(len(children))/self.ni; j < m; j++ {
+			queue = append(queue, children[j*self.ni+i])
+		}
+	}
+	index[i] = 0
+
+	return chain
+}
+
+func (self *Tree) traverse(terms []term, current *branch, chains [][]link, i uint,
+	weight float64) []term {
+
+	if i == self.ni {
+		return append(terms, term{position: current.position, weight: weight})
+	}
+	for _, link := range chains[i] {
+		if child, ok := current.children[link.index]; ok {
+			terms = self.traverse(terms, child, chains, i+1, weight*link.value)
+		}
+	}
+	return terms
+}
+
+// Gradient evaluates the gradients of an interpolant at multiple points using
+// multiple goroutines. The result matches the one of the function with the
+// same name up to rounding errors. The basis should implement
+// basis.Differentiator.
+func (self *Tree) Gradient(surpluses, points []float64) []float64 {
+	return self.derive(surpluses, points, 1)
+}
+
+// Hessian evaluates the Hessian matrices of an interpolant at multiple points
+// using multiple goroutines. The result matches the one of the function with
+// the same name up to rounding errors. The basis should implement
+// basis.Differentiator.
+func (self *Tree) Hessian(surpluses, points []float64) []float64 {
+	return self.derive(surpluses, points, 2)
+}
+
+// derive is similar to Estimate, except that the one-dimensional hierarchies
+// are descended toward the basis functions whose values or derivatives are
+// nonzero at the point, since the derivatives can be nonzero at the endpoints
+// of the supports, where the basis functions vanish.
+func (self *Tree) derive(surpluses, points []float64, order uint) []float64 {
+	differentiator := differentiator(self.computer)
+
+	ni, no := self.ni, self.no
+	nd := ni
+	if order == 2 {
+		nd *= ni
+	}
+	np := uint(len(points)) / ni
+	values := make([]float64, np*no*nd)
+	if self.nn == 0 {
+		return values
+	}
+
+	// The order of the derivative with respect to each dimension for each
+	// component of the result.
+	orders := make([]uint, nd*ni)
+	for l := uint(0); l < nd; l++ {
+		orders[l*ni+l%ni]++
+		if order == 2 {
+			orders[l*ni+l/ni]++
+		}
+	}
+
+	jobs := make(chan uint, np)
+	group := sync.WaitGroup{}
+	group.Add(int(np))
+
+	for i := uint(0); i < Workers; i++ {
+		go func() {
+			index := make([]uint64, ni)
+			chains := make([][]jet, ni)
+			partial := make([]float64, (ni+1)*nd)
+			for l := uint(0); l < nd; l++ {
+				partial[l] = 1.0
+			}
+			positions, weights, permutation := []uint{}, []float64{}, []uint{}
+
+			var traverse func(*branch, uint)
+			traverse = func(current *branch, k uint) {
+				if k == ni {
+					positions = append(positions, current.position)
+					weights = append(weights, partial[ni*nd:]...)
+					return
+				}
+				for _, jet := range chains[k] {
+					child, ok := current.children[jet.index]
+					if !ok {
+						continue
+					}
+					for l := uint(0); l < nd; l++ {
+						partial[(k+1)*nd+l] = partial[k*nd+l] * jet.values[orders[l*ni+k]]
+					}
+					traverse(child, k+1)
+				}
+			}
+
+			for j := range jobs {
+				point := points[j*ni : (j+1)*ni]
+				value := values[j*no*nd : (j+1)*no*nd]
+
+				for k := uint(0); k < ni; k++ {
+					chains[k] = self.descendJets(chains[k][:0], differentiator, index, point, k)
+				}
+				positions, weights = positions[:0], weights[:0]
+				traverse(self.root, 0)
+
+				// Accumulate in the order of the nodes as a full scan does.
+				permutation = permutation[:0]
+				for k := range positions {
+					permutation = append(permutation, uint(k))
+				}
+				sort.Slice(permutation, func(k, l int) bool {
+					return positions[permutation[k]] < positions[permutation[l]]
+				})
+				for _, k := range permutation {
+					weight := weights[k*nd : (k+1)*nd]
+					surplus := surpluses[positions[k]*no : (positions[k]+1)*no]
+					for l := uint(0); l < nd; l++ {
+						for m := uint(0); m < no; m++ {
+							value[m*nd+l] += weight[l] * surplus[m]
+						}
+					}
+				}
+
+				group.Done()
+			}
+		}()
+	}
+
+	for i := uint(0); i < np; i++ {
+		jobs <- i
+	}
+
+	group.Wait()
+	close(jobs)
+
+	return values
+}
+
+// descendJets is similar to descend, except that it also computes the first
+// and second derivatives of the one-dimensional basis functions.
+func (self *Tree) descendJets(chain []jet, differentiator basis.Differentiator,
+	index []uint64, point []float64, i uint) []jet {
+
+	queue := []uint64{0}
+	for len(queue) > 0 {
+		index[i] = queue[0]
+		queue = queue[1:]
+
+		values := [3]float64{
+			self.computer.Compute(index, point),
+			differentiator.Differentiate(index, point, i),
+			differentiator.DifferentiateTwice(index, point, i, i),
+		}
+		if values == [3]float64{} {
+			continue
+		}
+		chain = append(chain, jet{index: index[i], values: values})
+
+		if index[i]&internal.LEVEL_MASK >= self.levels[i] {
+			continue
+		}
+		children := self.refiner.RefineToward(index, i)
+		for j, m := uint(0), uint(len(children))/self.ni; j < m; j++ {
+			queue = append(queue, children[j*self.ni+i])
+		}
+	}
+	index[i] = 0
+
+	return chain
+}
+
+type byPosition []term
+
+func (self byPosition) Len() int           { return len(self) }
+func (self byPosition) Less(i, j int) bool { return self[i].position < self[j].position }
+func (self byPosition) Swap(i, j int)      { self[i], self[j] = self[j], self[i] }
@@ -0,0 +1,133 @@
+package internal
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/ready-steady/adapt/basis/polynomial"
+	"github.com/ready-steady/adapt/grid/equidistant"
+	"github.com/ready-steady/assert"
+)
+
+func BenchmarkTreeEstimate(b *testing.B) {
+	const (
+		ni = 5
+		no = 1
+		nl = 4
+		np = 1000
+	)
+
+	grid, basis := equidistant.NewClosed(ni), polynomial.NewClosed(ni, 1)
+	indices, surpluses, points := generateTree(grid.Refine, ni, no, nl, np)
+
+	tree := NewTree(grid, basis, ni, no)
+	tree.Push(indices)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		tree.Estimate(surpluses, points)
+	}
+}
+
+func TestTreeEstimate(t *testing.T) {
+	const (
+		ni = 3
+		no = 2
+		nl = 5
+		np = 1000
+	)
+
+	test := func(grid interface {
+		Refine([]uint64) []uint64
+		RefineToward([]uint64, uint) []uint64
+	}, basis interface {
+		Compute([]uint64, []float64) float64
+	}) {
+
+		indices, surpluses, points := generateTree(grid.Refine, ni, no, nl, np)
+		nn := uint(len(indices)) / ni
+
+		tree := NewTree(grid, basis, ni, no)
+		tree.Push(indices[:(nn/2)*ni])
+		tree.Push(indices[(nn/2)*ni:])
+
+		assert.Equal(tree.Estimate(surpluses, points),
+			Estimate(basis, indices, surpluses, points, ni, no), t)
+	}
+
+	test(equidistant.NewClosed(ni), polynomial.NewClosed(ni, 1))
+	test(equidistant.NewClosed(ni), polynomial.NewClosed(ni, 3))
+	test(equidistant.NewOpen(ni), polynomial.NewOpen(ni, 1))
+	test(equidistant.NewOpen(ni), polynomial.NewOpen(ni, 2))
+}
+
+func TestTreeDerivative(t *testing.T) {
+	const (
+		ni = 3
+		no = 2
+		nl = 5
+		np = 1000
+	)
+
+	test := func(grid interface {
+		Refine([]uint64) []uint64
+		RefineToward([]uint64, uint) []uint64
+	}, basis interface {
+		Compute([]uint64, []float64) float64
+		Differentiate([]uint64, []float64, uint) float64
+		DifferentiateTwice([]uint64, []float64, uint, uint) float64
+	}) {
+
+		indices, surpluses, points := generateTree(grid.Refine, ni, no, nl, np)
+
+		// Include points at which the derivatives are discontinuous.
+		points = append(points, 0.0, 0.5, 1.0, 0.25, 0.75, 0.125, 1.0, 0.0, 0.5)
+
+		tree := NewTree(grid, basis, ni, no)
+		tree.Push(indices)
+
+		assert.Close(tree.Gradient(surpluses, points),
+			Gradient(basis, indices, surpluses, points, ni, no), 1e-10, t)
+		assert.Close(tree.Hessian(surpluses, points),
+			Hessian(basis, indices, surpluses, points, ni, no), 1e-10, t)
+	}
+
+	test(equidistant.NewClosed(ni), polynomial.NewClosed(ni, 1))
+	test(equidistant.NewClosed(ni), polynomial.NewClosed(ni, 3))
+	test(equidistant.NewOpen(ni), polynomial.NewOpen(ni, 1))
+	test(equidistant.NewOpen(ni), polynomial.NewOpen(ni, 2))
+}
+
+func generateTree(refine func([]uint64) []uint64, ni, no, nl, np uint) ([]uint64,
+	[]float64, []float64) {
+
+	generator := rand.New(rand.NewSource(0))
+
+	unique := NewUnique(ni)
+	indices := unique.Distil(make([]uint64, ni))
+	parents := indices
+	for i := uint(1); i < nl; i++ {
+		children := unique.Distil(refine(parents))
+		nc := uint(len(children)) / ni
+		parents = []uint64{}
+		for j := uint(0); j < nc; j++ {
+			if generator.Float64() < 0.5 {
+				parents = append(parents, children[j*ni:(j+1)*ni]...)
+			}
+		}
+		indices = append(indices, parents...)
+	}
+
+	nn := uint(len(indices)) / ni
+	surpluses := make([]float64, nn*no)
+	for i := range surpluses {
+		surpluses[i] = generator.Float64()
+	}
+	points := make([]float64, np*ni)
+	for i := range points {
+		points[i] = generator.Float64()
+	}
+
+	return indices, surpluses, points
+}
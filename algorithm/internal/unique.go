@@ -23,3 +23,22 @@ func (self *Unique) Distil(indices []uint64) []uint64 {
 	}
 	return append(unique, indices[k*ni:]...)
 }
+
+// Count returns the number of indices that have not been seen yet, counting
+// repetitions only once, without marking them as seen.
+func (self *Unique) Count(indices []uint64) uint {
+	ni := self.ni
+	nn := uint(len(indices)) / ni
+	count, seen := uint(0), make(map[string]bool)
+	for i := uint(0); i < nn; i++ {
+		index := indices[i*ni : (i+1)*ni]
+		if _, found := self.Get(index); found {
+			continue
+		}
+		if key := self.Key(index); !seen[key] {
+			seen[key] = true
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,36 @@
+package local
+
+import (
+	"sync"
+
+	"github.com/ready-steady/adapt/algorithm"
+	"github.com/ready-steady/adapt/algorithm/internal"
+)
+
+// cache keeps the estimator of the most recently evaluated interpolant so that
+// consecutive evaluations do not rebuild it. The estimator is rebuilt when the
+// interpolant changes or gets new nodes.
+type cache struct {
+	sync.Mutex
+
+	surrogate *algorithm.Surrogate
+	nodes     uint
+	estimator internal.Estimator
+}
+
+func (self *cache) load(surrogate *algorithm.Surrogate,
+	plant func() internal.Estimator) internal.Estimator {
+
+	self.Lock()
+	defer self.Unlock()
+	if self.surrogate != surrogate || self.nodes != surrogate.Nodes {
+		self.surrogate, self.nodes, self.estimator = surrogate, surrogate.Nodes, plant()
+	}
+	return self.estimator
+}
+
+func (self *cache) store(surrogate *algorithm.Surrogate, estimator internal.Estimator) {
+	self.Lock()
+	defer self.Unlock()
+	self.surrogate, self.nodes, self.estimator = surrogate, surrogate.Nodes, estimator
+}
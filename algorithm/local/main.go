@@ -3,8 +3,11 @@
 package local
 
 import (
+	"context"
+
 	"github.com/ready-steady/adapt/algorithm"
 	"github.com/ready-steady/adapt/algorithm/internal"
+	"github.com/ready-steady/adapt/algorithm/internal/core"
 	"github.com/ready-steady/adapt/basis"
 	"github.com/ready-steady/adapt/grid"
 )
@@ -14,8 +17,13 @@ type Algorithm struct {
 	ni uint
 	no uint
 
-	grid  Grid
-	basis Basis
+	grid     Grid
+	basis    Basis
+	domain   *algorithm.Domain
+	observer algorithm.Observer
+	record   bool
+
+	cache *cache
 }
 
 // Basis is an interpolation basis.
@@ -25,6 +33,11 @@ type Basis interface {
 }
 
 // Grid is an interpolation grid.
+//
+// If the grid also implements grid.RefinerToward, interpolants are evaluated
+// by descending the hierarchy of the basis functions toward each point instead
+// of scanning all of them, which requires the basis functions to have nested
+// local supports; see internal.Tree.
 type Grid interface {
 	grid.Computer
 }
@@ -37,32 +50,120 @@ func New(inputs, outputs uint, grid Grid, basis Basis) *Algorithm {
 
 		grid:  grid,
 		basis: basis,
+
+		cache: &cache{},
+	}
+}
+
+// Restrict sets the domain of the inputs, which is the unit hypercube by
+// default. The nodes passed to the target, the points passed to Evaluate, and
+// the integral of the surrogate are then expressed with respect to the domain.
+func (self *Algorithm) Restrict(domain *algorithm.Domain) {
+	ni := self.ni
+	if domain != nil && (uint(len(domain.Lower)) != ni || uint(len(domain.Upper)) != ni) {
+		panic("the domain should have as many dimensions as there are inputs")
 	}
+	self.domain = domain
+}
+
+// Monitor sets an observer that is notified after each iteration.
+func (self *Algorithm) Monitor(observer algorithm.Observer) {
+	self.observer = observer
+}
+
+// Record enables recording of the convergence history of interpolants; see
+// algorithm.Surrogate.History.
+func (self *Algorithm) Record() {
+	self.record = true
 }
 
 // Compute constructs an interpolant for a function.
 func (self *Algorithm) Compute(target algorithm.Target,
 	strategy algorithm.Strategy) *algorithm.Surrogate {
 
-	ni, no := self.ni, self.no
-	surrogate := algorithm.NewSurrogate(ni, no)
-	for s := strategy.First(surrogate); s != nil; s = strategy.Next(s, surrogate) {
-		s.Volumes = internal.Measure(self.basis, s.Indices, ni)
-		s.Nodes = self.grid.Compute(s.Indices)
-		s.Values = algorithm.Invoke(target, s.Nodes, ni, no)
-		s.Estimates = internal.Estimate(self.basis, surrogate.Indices,
-			surrogate.Surpluses, s.Nodes, ni, no)
-		s.Surpluses = internal.Subtract(s.Values, s.Estimates)
-		s.Scores = score(strategy, s, ni, no)
-		surrogate.Push(s.Indices, s.Surpluses, s.Volumes)
-	}
+	process := self.Start(strategy).Process
+	surrogate := core.Compute(process, target)
+	self.cache.store(surrogate, process.Estimator())
 	return surrogate
 }
 
-// Evaluate computes the values of an interpolant at a set of points.
+// ComputeContext constructs an interpolant for a function that can fail and be
+// cancelled. If the context is cancelled or the function fails, the process
+// stops, and the interpolant constructed from the completed iterations is
+// returned together with the error.
+func (self *Algorithm) ComputeContext(ctx context.Context, target algorithm.ContextTarget,
+	strategy algorithm.Strategy) (*algorithm.Surrogate, error) {
+
+	process := self.Start(strategy).Process
+	surrogate, err := core.ComputeContext(ctx, process, target)
+	self.cache.store(surrogate, process.Estimator())
+	return surrogate, err
+}
+
+// Evaluate computes the values of an interpolant at a set of points. The
+// structure used for the evaluation is built once per interpolant and reused
+// by subsequent calls with the same interpolant.
 func (self *Algorithm) Evaluate(surrogate *algorithm.Surrogate, points []float64) []float64 {
-	return internal.Estimate(self.basis, surrogate.Indices, surrogate.Surpluses,
-		points, surrogate.Inputs, surrogate.Outputs)
+	if surrogate.Domain != nil {
+		points = surrogate.Domain.Backward(points)
+	}
+	return self.estimator(surrogate).Estimate(surrogate.Surpluses, points)
+}
+
+// EvaluateGradient computes the gradients of an interpolant at a set of
+// points. For each point, the result contains the Jacobian matrix of the
+// outputs with respect to the inputs, which is an outputs-by-inputs matrix in
+// row-major order. The basis should implement basis.Differentiator.
+func (self *Algorithm) EvaluateGradient(surrogate *algorithm.Surrogate,
+	points []float64) []float64 {
+
+	return core.Gradient(self.estimator(surrogate), surrogate, points)
+}
+
+// EvaluateHessian computes the Hessian matrices of an interpolant at a set of
+// points. For each point, the result contains an outputs-by-inputs-by-inputs
+// array in row-major order, which is composed of the Hessian matrices of the
+// outputs. The basis should implement basis.Differentiator.
+func (self *Algorithm) EvaluateHessian(surrogate *algorithm.Surrogate,
+	points []float64) []float64 {
+
+	return core.Hessian(self.estimator(surrogate), surrogate, points)
+}
+
+func (self *Algorithm) config() core.Config {
+	return core.Config{
+		Inputs:  self.ni,
+		Outputs: self.no,
+
+		Grid:   self.grid,
+		Basis:  self.basis,
+		Domain: self.domain,
+
+		Observer: self.observer,
+		Record:   self.record,
+
+		Plant: func() internal.Estimator {
+			return self.plant(nil)
+		},
+		Score: score,
+	}
+}
+
+func (self *Algorithm) estimator(surrogate *algorithm.Surrogate) internal.Estimator {
+	return self.cache.load(surrogate, func() internal.Estimator {
+		return self.plant(surrogate.Indices)
+	})
+}
+
+func (self *Algorithm) plant(indices []uint64) internal.Estimator {
+	var estimator internal.Estimator
+	if refiner, ok := self.grid.(grid.RefinerToward); ok {
+		estimator = internal.NewTree(refiner, self.basis, self.ni, self.no)
+	} else {
+		estimator = internal.NewScan(self.basis, self.ni, self.no)
+	}
+	estimator.Push(indices)
+	return estimator
 }
 
 func score(strategy algorithm.Strategy, state *algorithm.State, ni, no uint) []float64 {
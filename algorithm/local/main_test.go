@@ -1,8 +1,15 @@
 package local
 
 import (
+	"context"
+	"errors"
+	"math"
 	"testing"
+	"time"
 
+	"github.com/ready-steady/adapt/basis/polynomial"
+	"github.com/ready-steady/adapt/grid/equidistant"
+	"github.com/ready-steady/adapt/internal"
 	"github.com/ready-steady/assert"
 
 	interpolation "github.com/ready-steady/adapt/algorithm"
@@ -72,6 +79,31 @@ func TestParabola(t *testing.T) {
 	assert.Close(values, fixture.values, 1e-6, t)
 }
 
+func TestProcess(t *testing.T) {
+	fixture := &fixtureBox
+	algorithm, strategy := prepare(fixture)
+	ni, no := fixture.surrogate.Inputs, fixture.surrogate.Outputs
+
+	process := algorithm.Start(strategy)
+	for !process.Done() {
+		positions, nodes := process.Ask()
+		nn := uint(len(positions))
+		values := make([]float64, nn*no)
+		for i := uint(0); i < nn; i++ {
+			fixture.target(nodes[i*ni:(i+1)*ni], values[i*no:(i+1)*no])
+		}
+		assert.Equal(process.Tell(positions[nn-1:], values[(nn-1)*no:]), nil, t)
+		if nn > 1 {
+			assert.Equal(process.Tell(positions[nn-1:], values[(nn-1)*no:]) != nil, true, t)
+			repeated := []uint{positions[0], positions[0]}
+			assert.Equal(process.Tell(repeated, append(values[:no:no], values[:no]...)) != nil,
+				true, t)
+			assert.Equal(process.Tell(positions[:nn-1], values[:(nn-1)*no]), nil, t)
+		}
+	}
+	assert.Equal(process.Surrogate(), fixture.surrogate, t)
+}
+
 func TestStep(t *testing.T) {
 	fixture := &fixtureStep
 	algorithm, strategy := prepare(fixture)
@@ -82,3 +114,258 @@ func TestStep(t *testing.T) {
 	values := algorithm.Evaluate(surrogate, fixture.points)
 	assert.Equal(values, fixture.values, t)
 }
+
+func TestComputeContext(t *testing.T) {
+	fixture := &fixtureBox
+	algorithm, strategy := prepare(fixture)
+
+	surrogate, err := algorithm.ComputeContext(context.Background(),
+		func(_ context.Context, x, y []float64) error {
+			fixture.target(x, y)
+			return nil
+		}, strategy)
+	assert.Equal(err, nil, t)
+	assert.Equal(surrogate, fixture.surrogate, t)
+
+	failure := errors.New("failure")
+	algorithm, strategy = prepare(fixture)
+	surrogate, err = algorithm.ComputeContext(context.Background(),
+		func(_ context.Context, x, y []float64) error {
+			if x[0] == 0.125 {
+				return failure
+			}
+			fixture.target(x, y)
+			return nil
+		}, strategy)
+	assert.Equal(err, failure, t)
+	assert.Equal(surrogate.Nodes < fixture.surrogate.Nodes, true, t)
+	assert.Equal(interpolation.Validate(surrogate.Indices, surrogate.Inputs,
+		fixture.grid), true, t)
+}
+
+func TestLimit(t *testing.T) {
+	fixture := &fixtureHat
+
+	algorithm, strategy := prepare(fixture)
+	strategy.(*Strategy).Limit(100, 0)
+	surrogate := algorithm.Compute(fixture.target, strategy)
+	assert.Equal(surrogate.Nodes <= 100, true, t)
+	assert.Equal(surrogate.Nodes > 90, true, t)
+	assert.Equal(interpolation.Validate(surrogate.Indices, surrogate.Inputs,
+		fixture.grid), true, t)
+
+	algorithm, strategy = prepare(fixture)
+	strategy.(*Strategy).Limit(0, time.Nanosecond)
+	surrogate = algorithm.Compute(fixture.target, strategy)
+	assert.Equal(surrogate.Nodes, uint(1), t)
+}
+
+func TestRecord(t *testing.T) {
+	fixture := &fixtureHat
+	algorithm, strategy := prepare(fixture)
+	algorithm.Record()
+
+	surrogate := algorithm.Compute(fixture.target, strategy)
+	history := surrogate.History
+	nh := len(history)
+	assert.Equal(nh > 1, true, t)
+
+	nn := uint(0)
+	for _, iteration := range history {
+		nn += iteration.Nodes
+		assert.Equal(iteration.Total, nn, t)
+	}
+	assert.Equal(nn, surrogate.Nodes, t)
+	assert.Equal(history[nh-1].Integral, surrogate.Integral, t)
+
+	surrogate.History = nil
+	assert.Equal(surrogate, fixture.surrogate, t)
+}
+
+func TestTolerate(t *testing.T) {
+	const (
+		ni = 2
+	)
+
+	grid, basis := equidistant.NewClosed(ni), polynomial.NewClosed(ni, 1)
+	target := func(x []float64) float64 {
+		return math.Exp(-10.0 * (x[0] - 0.3) * (x[0] - 0.3) * x[1])
+	}
+
+	algorithm := New(ni, 1, grid, basis)
+	strategy := NewStrategy(ni, 1, grid, 1, 10, 1e-3)
+	expected := algorithm.Compute(func(x, y []float64) {
+		y[0] = target(x)
+	}, strategy)
+
+	algorithm = New(ni, 2, grid, basis)
+	strategy = NewStrategy(ni, 2, grid, 1, 10, 1e-3)
+	strategy.Weigh([]float64{1.0, 0.0})
+	surrogate := algorithm.Compute(func(x, y []float64) {
+		y[0], y[1] = target(x), 1e6*target(x)
+	}, strategy)
+	assert.Equal(surrogate.Indices, expected.Indices, t)
+
+	strategy = NewStrategy(ni, 2, grid, 1, 10, 42.0)
+	strategy.Tolerate([]float64{1e-3, math.Inf(1.0)})
+	strategy.Collapse(interpolation.NormSum)
+	surrogate = algorithm.Compute(func(x, y []float64) {
+		y[0], y[1] = target(x), 1e6*target(x)
+	}, strategy)
+	assert.Equal(surrogate.Indices, expected.Indices, t)
+}
+
+func TestBoundLevels(t *testing.T) {
+	fixture := &fixtureCube
+	algorithm, strategy := prepare(fixture)
+	strategy.(*Strategy).BoundLevels([]uint{0, 6}, []uint{3, 10})
+
+	surrogate := algorithm.Compute(fixture.target, strategy)
+	assert.Equal(interpolation.Validate(surrogate.Indices, surrogate.Inputs,
+		fixture.grid), true, t)
+
+	levels := make([]uint64, 2)
+	for i, index := range surrogate.Indices {
+		if level := internal.LEVEL_MASK & index; level > levels[i%2] {
+			levels[i%2] = level
+		}
+	}
+	assert.Equal(levels[0], uint64(3), t)
+	assert.Equal(levels[1] >= 6, true, t)
+}
+
+func TestBoundLevelsBelow(t *testing.T) {
+	const (
+		ni = 2
+		no = 1
+	)
+
+	grid, basis := equidistant.NewClosed(ni), polynomial.NewClosed(ni, 1)
+	algorithm := New(ni, no, grid, basis)
+	target := func(x, y []float64) {
+		y[0] = x[0] * x[0]
+	}
+
+	strategy := NewStrategy(ni, no, grid, 1, 10, 1e-2)
+	expected := algorithm.Compute(target, strategy)
+
+	strategy = NewStrategy(ni, no, grid, 1, 10, 1e-2)
+	strategy.BoundLevels([]uint{0, 3}, []uint{10, 10})
+	surrogate := algorithm.Compute(target, strategy)
+	assert.Equal(maxLevels(surrogate), []uint64{maxLevels(expected)[0], 3}, t)
+	assert.Equal(surrogate.Nodes <= 2*expected.Nodes, true, t)
+}
+
+func maxLevels(surrogate *interpolation.Surrogate) []uint64 {
+	levels := make([]uint64, surrogate.Inputs)
+	for i, index := range surrogate.Indices {
+		j := uint(i) % surrogate.Inputs
+		if level := internal.LEVEL_MASK & index; level > levels[j] {
+			levels[j] = level
+		}
+	}
+	return levels
+}
+
+func TestWeighLevels(t *testing.T) {
+	fixture := &fixtureCube
+	algorithm, strategy := prepare(fixture)
+	strategy.(*Strategy).WeighLevels([]float64{1.0, 4.0})
+	ni, no := fixture.surrogate.Inputs, fixture.surrogate.Outputs
+	lmax := uint64(strategy.(*Strategy).lmax)
+
+	process := algorithm.Start(strategy)
+	for !process.Done() {
+		positions, nodes := process.Ask()
+		indices := process.State().Indices
+		for _, i := range positions {
+			index := indices[i*ni : (i+1)*ni]
+			norm := internal.LEVEL_MASK&index[0] + 4*(internal.LEVEL_MASK&index[1])
+			assert.Equal(norm <= lmax, true, t)
+		}
+		values := interpolation.Invoke(fixture.target, nodes, ni, no)
+		assert.Equal(process.Tell(positions, values), nil, t)
+	}
+}
+
+func TestDomain(t *testing.T) {
+	const (
+		ni = 2
+		no = 1
+	)
+
+	grid, basis := equidistant.NewClosed(ni), polynomial.NewClosed(ni, 1)
+	domain := interpolation.NewDomain([]float64{1.0, -1.0}, []float64{3.0, 2.0})
+
+	algorithm := New(ni, no, grid, basis)
+	algorithm.Restrict(domain)
+	surrogate := algorithm.Compute(func(x, y []float64) {
+		y[0] = x[0] * x[1]
+	}, NewStrategy(ni, no, grid, 1, 10, 1e-4))
+	assert.Equal(surrogate.Domain, domain, t)
+	assert.Close(surrogate.Integral, []float64{6.0}, 1e-14, t)
+	assert.Close(algorithm.Evaluate(surrogate, []float64{2.9, 1.9}), []float64{5.51}, 1e-14, t)
+
+	defer func() {
+		assert.Equal(recover() != nil, true, t)
+	}()
+	algorithm.Restrict(interpolation.NewDomain([]float64{1.0}, []float64{3.0}))
+}
+
+func TestEvaluateCache(t *testing.T) {
+	fixture := &fixtureBox
+	algorithm, strategy := prepare(fixture)
+	ni, no := fixture.surrogate.Inputs, fixture.surrogate.Outputs
+
+	surrogate := algorithm.Compute(fixture.target, strategy)
+	estimator := algorithm.cache.estimator
+	values := algorithm.Evaluate(surrogate, fixture.points)
+	assert.Equal(algorithm.cache.estimator == estimator, true, t)
+
+	nn := surrogate.Nodes / 2
+	partial := interpolation.NewSurrogate(ni, no)
+	partial.Push(surrogate.Indices[:nn*ni], surrogate.Surpluses[:nn*no], make([]float64, nn))
+
+	expected := New(ni, no, fixture.grid, fixture.basis).Evaluate(partial, fixture.points)
+	assert.Equal(algorithm.Evaluate(partial, fixture.points), expected, t)
+	assert.Equal(algorithm.cache.estimator == estimator, false, t)
+
+	partial.Push(surrogate.Indices[nn*ni:], surrogate.Surpluses[nn*no:],
+		make([]float64, surrogate.Nodes-nn))
+	assert.Equal(algorithm.Evaluate(partial, fixture.points), values, t)
+}
+
+func TestEvaluateGradient(t *testing.T) {
+	const (
+		ni = 2
+		no = 2
+	)
+
+	grid, basis := equidistant.NewClosed(ni), polynomial.NewClosed(ni, 2)
+	domain := interpolation.NewDomain([]float64{0.0, -1.0}, []float64{2.0, 1.0})
+
+	algorithm := New(ni, no, grid, basis)
+	algorithm.Restrict(domain)
+	strategy := NewStrategy(ni, no, grid, 2, 10, 1e-10)
+
+	surrogate := algorithm.Compute(func(x, y []float64) {
+		y[0] = x[0] * x[0] * x[1]
+		y[1] = x[0] + 3.0*x[1]*x[1]
+	}, strategy)
+
+	// The last points lie at the nodes and on the boundary of the domain.
+	points := []float64{0.3, -0.7, 1.1, 0.2, 1.7, 0.9, 1.0, 0.0, 0.0, -1.0, 2.0, 1.0, 0.5, 1.0}
+	gradients := algorithm.EvaluateGradient(surrogate, points)
+	hessians := algorithm.EvaluateHessian(surrogate, points)
+	for i := 0; i < len(points)/2; i++ {
+		x := points[2*i : 2*(i+1)]
+		assert.Close(gradients[4*i:4*(i+1)], []float64{
+			2.0 * x[0] * x[1], x[0] * x[0],
+			1.0, 6.0 * x[1],
+		}, 1e-10, t)
+		assert.Close(hessians[8*i:8*(i+1)], []float64{
+			2.0 * x[1], 2.0 * x[0], 2.0 * x[0], 0.0,
+			0.0, 0.0, 0.0, 6.0,
+		}, 1e-8, t)
+	}
+}
@@ -1,6 +1,9 @@
 package local
 
 import (
+	"sort"
+	"time"
+
 	"github.com/ready-steady/adapt/algorithm"
 	"github.com/ready-steady/adapt/algorithm/internal"
 	"github.com/ready-steady/adapt/grid"
@@ -13,10 +16,17 @@ type Strategy struct {
 
 	guide Guide
 
-	lmin uint
-	lmax uint
-	εs   float64
+	lmin  uint
+	lmax  uint
+	limit *internal.Limit
+	εs    float64
+
+	norm    algorithm.Norm
+	weights []float64
+	errors  []float64
+	scale   []float64
 
+	budget *internal.Budget
 	unique *internal.Unique
 }
 
@@ -36,40 +46,179 @@ func NewStrategy(inputs, outputs uint, guide Guide, minLevel, maxLevel uint,
 
 		guide: guide,
 
-		lmin: minLevel,
-		lmax: maxLevel,
-		εs:   scoreError,
+		lmin:  minLevel,
+		lmax:  maxLevel,
+		limit: internal.NewLimit(inputs),
+		εs:    scoreError,
+
+		norm: algorithm.NormMaximum,
 
+		budget: internal.NewBudget(0, 0),
 		unique: internal.NewUnique(inputs),
 	}
 }
 
+// Limit sets the maximum number of nodes, which is the number of evaluations of
+// the target, and the maximum running time. A zero value means no limit. When
+// the number of nodes is limited, the nodes with the highest scores are refined
+// first, and the refinements that do not fit in the remaining budget are
+// skipped.
+func (self *Strategy) Limit(nodes uint, duration time.Duration) {
+	self.budget = internal.NewBudget(nodes, duration)
+}
+
+// Timed checks if the running time is limited.
+func (self *Strategy) Timed() bool {
+	return self.budget.Timed()
+}
+
+// BoundLevels sets the minimal and maximal levels of each dimension, which
+// apply in addition to the minimal and maximal levels given at creation.
+func (self *Strategy) BoundLevels(minLevels, maxLevels []uint) {
+	self.limit.Bound(minLevels, maxLevels)
+}
+
+// WeighLevels sets the weights of the dimensions in the level norm that the
+// minimal and maximal levels given at creation are compared with. By default,
+// the norm is the sum of the levels of the dimensions.
+func (self *Strategy) WeighLevels(weights []float64) {
+	self.limit.Weigh(weights)
+}
+
+// Weigh sets the weights of the outputs used for scoring nodes.
+func (self *Strategy) Weigh(weights []float64) {
+	if uint(len(weights)) != self.no {
+		panic("the number of weights should be equal to the number of outputs")
+	}
+	self.weights = append([]float64(nil), weights...)
+	self.rescale()
+}
+
+// Collapse sets the norm used for collapsing the weighted outputs of a node
+// into a single score. The default is the maximum norm.
+func (self *Strategy) Collapse(norm algorithm.Norm) {
+	self.norm = norm
+}
+
+// Tolerate sets the score errors of the outputs, which replace the score error
+// given at creation. A node is then refined if the norm of its weighted
+// surpluses divided by the errors exceeds one.
+func (self *Strategy) Tolerate(errors []float64) {
+	if uint(len(errors)) != self.no {
+		panic("the number of errors should be equal to the number of outputs")
+	}
+	self.errors = append([]float64(nil), errors...)
+	self.εs = 1.0
+	self.rescale()
+}
+
 func (self *Strategy) First(_ *algorithm.Surrogate) *algorithm.State {
 	lndex := make([]uint64, self.ni)
-	return &algorithm.State{Indices: self.guide.Index(lndex)}
+	indices := self.guide.Index(lndex)
+	self.budget.Start()
+	self.budget.Consume(uint(len(indices)) / self.ni)
+	return &algorithm.State{Indices: indices}
 }
 
 func (self *Strategy) Next(state *algorithm.State, _ *algorithm.Surrogate) *algorithm.State {
-	indices := self.unique.Distil(self.guide.Refine(filter(state.Indices,
-		state.Scores, self.lmin, self.lmax, self.εs, self.ni)))
+	if self.budget.Expired() {
+		return nil
+	}
+	var indices []uint64
+	if self.budget.Limited() {
+		indices = self.unique.Distil(self.limit.Filter(self.guide.Refine(
+			self.choose(state.Indices, state.Scores)), self.lmax))
+	} else {
+		indices = self.unique.Distil(self.limit.Filter(self.guide.Refine(
+			filter(state.Indices, state.Scores, self.limit, self.lmin, self.lmax, self.εs,
+				self.ni)), self.lmax))
+	}
 	if len(indices) == 0 {
 		return nil
 	}
+	self.budget.Consume(uint(len(indices)) / self.ni)
 	return &algorithm.State{
 		Indices: indices,
 	}
 }
 
 func (self *Strategy) Score(element *algorithm.Element) float64 {
-	return internal.MaxAbsolute(element.Surplus)
+	return self.norm.Compute(element.Surplus, self.scale)
 }
 
-func filter(indices []uint64, scores []float64, lmin, lmax uint, εs float64, ni uint) []uint64 {
+func (self *Strategy) rescale() {
+	self.scale = make([]float64, self.no)
+	for i := range self.scale {
+		self.scale[i] = 1.0
+		if self.weights != nil {
+			self.scale[i] *= self.weights[i]
+		}
+		if self.errors != nil {
+			self.scale[i] /= self.errors[i]
+		}
+	}
+}
+
+func (self *Strategy) choose(indices []uint64, scores []float64) []uint64 {
+	ni := self.ni
 	nn := uint(len(scores))
-	levels := internal.Levelize(indices, ni)
+
+	order := []uint{}
+	for i := uint(0); i < nn; i++ {
+		if !stop(indices[i*ni:(i+1)*ni], scores[i], self.limit, self.lmin, self.lmax, self.εs) {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+
+	chosen := make([]bool, nn)
+	history, consumed := internal.NewHistory(ni), uint(0)
+	for _, i := range order {
+		children := self.limit.Filter(self.guide.Refine(indices[i*ni:(i+1)*ni]), self.lmax)
+		count, fresh := uint(0), []uint64{}
+		for j, m := uint(0), uint(len(children))/ni; j < m; j++ {
+			child := children[j*ni : (j+1)*ni]
+			if _, found := self.unique.Get(child); found {
+				continue
+			}
+			if _, found := history.Get(child); found {
+				continue
+			}
+			fresh = append(fresh, child...)
+			count++
+		}
+		if !self.budget.Fits(consumed + count) {
+			continue
+		}
+		for j := uint(0); j < count; j++ {
+			history.Set(fresh[j*ni:(j+1)*ni], 0)
+		}
+		consumed += count
+		chosen[i] = true
+	}
+
+	parents := []uint64{}
+	for i := uint(0); i < nn; i++ {
+		if chosen[i] {
+			parents = append(parents, indices[i*ni:(i+1)*ni]...)
+		}
+	}
+	return parents
+}
+
+func filter(indices []uint64, scores []float64, limit *internal.Limit, lmin, lmax uint,
+	εs float64, ni uint) []uint64 {
+
+	nn := uint(len(scores))
+	stops := make([]bool, nn)
+	for i := uint(0); i < nn; i++ {
+		stops[i] = stop(indices[i*ni:(i+1)*ni], scores[i], limit, lmin, lmax, εs)
+	}
 	na, ne := uint(0), nn
 	for i, j := uint(0), uint(0); i < nn; i++ {
-		if levels[i] >= uint64(lmin) && (scores[i] <= εs || levels[i] >= uint64(lmax)) {
+		if stops[i] {
 			j++
 			continue
 		}
@@ -83,3 +232,9 @@ func filter(indices []uint64, scores []float64, lmin, lmax uint, εs float64, ni
 	}
 	return indices[:na*ni]
 }
+
+func stop(index []uint64, score float64, limit *internal.Limit, lmin, lmax uint,
+	εs float64) bool {
+
+	return !limit.Below(index, lmin) && (score <= εs || limit.Reached(index, lmax))
+}
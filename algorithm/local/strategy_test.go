@@ -3,6 +3,7 @@ package local
 import (
 	"testing"
 
+	"github.com/ready-steady/adapt/algorithm/internal"
 	"github.com/ready-steady/assert"
 )
 
@@ -14,19 +15,44 @@ func TestFilter(t *testing.T) {
 
 	var indices []uint64
 
+	limit := internal.NewLimit(ni)
+
 	indices = []uint64{1, 2, 3, 4, 5, 6, 7, 8}
-	indices = filter(indices, []float64{1.0, 2.0, 3.0, 4.0}, 1, 20, εl, ni)
+	indices = filter(indices, []float64{1.0, 2.0, 3.0, 4.0}, limit, 1, 20, εl, ni)
 	assert.Equal(indices, []uint64{1, 2, 3, 4, 5, 6, 7, 8}, t)
 
 	indices = []uint64{1, 2, 3, 4, 5, 6, 7, 8}
-	indices = filter(indices, []float64{0.0, 2.0, 3.0, 4.0}, 4, 20, εl, ni)
+	indices = filter(indices, []float64{0.0, 2.0, 3.0, 4.0}, limit, 4, 20, εl, ni)
 	assert.Equal(indices, []uint64{1, 2, 3, 4, 5, 6, 7, 8}, t)
 
 	indices = []uint64{1, 2, 3, 4, 5, 6, 7, 8}
-	indices = filter(indices, []float64{0.0, 2.0, 3.0, 4.0}, 1, 20, εl, ni)
+	indices = filter(indices, []float64{0.0, 2.0, 3.0, 4.0}, limit, 1, 20, εl, ni)
 	assert.Equal(indices, []uint64{3, 4, 5, 6, 7, 8}, t)
 
 	indices = []uint64{1, 2, 3, 4, 5, 6, 7, 8}
-	indices = filter(indices, []float64{1.0, 2.0, 3.0, 4.0}, 1, 10, εl, ni)
+	indices = filter(indices, []float64{1.0, 2.0, 3.0, 4.0}, limit, 1, 10, εl, ni)
 	assert.Equal(indices, []uint64{1, 2, 3, 4}, t)
 }
+
+func TestFilterLimit(t *testing.T) {
+	const (
+		εl = 0.0
+		ni = 2
+	)
+
+	var indices []uint64
+
+	limit := internal.NewLimit(ni)
+	limit.Bound([]uint{0, 2}, []uint{20, 20})
+
+	indices = []uint64{1, 2, 3, 4, 0, 1, 5, 1}
+	indices = filter(indices, []float64{0.0, 0.0, 0.0, 0.0}, limit, 1, 20, εl, ni)
+	assert.Equal(indices, []uint64{0, 1}, t)
+
+	limit = internal.NewLimit(ni)
+	limit.Weigh([]float64{1.0, 0.5})
+
+	indices = []uint64{1, 2, 3, 4, 5, 6, 7, 8}
+	indices = filter(indices, []float64{1.0, 2.0, 3.0, 4.0}, limit, 1, 10, εl, ni)
+	assert.Equal(indices, []uint64{1, 2, 3, 4, 5, 6}, t)
+}
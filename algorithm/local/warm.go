@@ -0,0 +1,24 @@
+package local
+
+import (
+	"github.com/ready-steady/adapt/algorithm"
+	"github.com/ready-steady/adapt/algorithm/internal/core"
+)
+
+// Refine constructs an interpolant for a function starting from a previously
+// computed one, for instance, with a tighter tolerance or for a recalibrated
+// function. The values of the function at the nodes of the previous
+// interpolant are taken from the values argument, which is ordered as the
+// nodes, or, if it is nil, from the previous interpolant itself.
+//
+// Since the refinement of the basic strategy is not downward closed, new nodes
+// can alter the surpluses of the previous ones. The interpolation process is
+// therefore repeated using the known values, which reconstructs the state of
+// the strategy, and the function is invoked only at the nodes that are new.
+// Strategies implementing algorithm.Resumer continue from the previous
+// interpolant instead.
+func (self *Algorithm) Refine(target algorithm.Target, strategy algorithm.Strategy,
+	surrogate *algorithm.Surrogate, values []float64) *algorithm.Surrogate {
+
+	return core.Refine(self.config(), target, strategy, surrogate, values)
+}
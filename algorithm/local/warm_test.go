@@ -0,0 +1,49 @@
+package local
+
+import (
+	"math"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ready-steady/adapt/basis/polynomial"
+	"github.com/ready-steady/adapt/grid/equidistant"
+	"github.com/ready-steady/assert"
+)
+
+func TestRefine(t *testing.T) {
+	const (
+		ni = 2
+		no = 1
+	)
+
+	grid, basis := equidistant.NewClosed(ni), polynomial.NewClosed(ni, 1)
+	algorithm := New(ni, no, grid, basis)
+
+	count := int64(0)
+	target := func(x, y []float64) {
+		atomic.AddInt64(&count, 1)
+		y[0] = math.Exp(-10.0*x[0]*x[0]) * math.Sin(5.0*x[1])
+	}
+
+	coarse := algorithm.Compute(target, NewStrategy(ni, no, grid, 1, 10, 1e-2))
+	fine := algorithm.Compute(target, NewStrategy(ni, no, grid, 1, 10, 1e-3))
+
+	nodes := grid.Compute(coarse.Indices)
+	values := make([]float64, coarse.Nodes*no)
+	for i := uint(0); i < coarse.Nodes; i++ {
+		target(nodes[i*ni:(i+1)*ni], values[i*no:(i+1)*no])
+	}
+
+	count = 0
+	surrogate := algorithm.Refine(target, NewStrategy(ni, no, grid, 1, 10, 1e-3),
+		coarse, values)
+	assert.Equal(surrogate, fine, t)
+	assert.Equal(count, int64(fine.Nodes-coarse.Nodes), t)
+
+	count = 0
+	surrogate = algorithm.Refine(target, NewStrategy(ni, no, grid, 1, 10, 1e-3),
+		coarse, nil)
+	assert.Equal(surrogate.Indices, fine.Indices, t)
+	assert.Close(surrogate.Surpluses, fine.Surpluses, 1e-14, t)
+	assert.Equal(count, int64(fine.Nodes-coarse.Nodes), t)
+}
@@ -0,0 +1,48 @@
+package algorithm
+
+import (
+	"math"
+
+	"github.com/ready-steady/adapt/algorithm/internal"
+)
+
+// Norm is a means of collapsing the outputs of a target into a single value.
+type Norm uint
+
+const (
+	NormMaximum   Norm = iota // Maximum of the absolute values
+	NormSum                   // Sum of the absolute values
+	NormEuclidean             // Euclidean norm
+)
+
+// Compute computes the norm of a vector whose elements are multiplied by a set
+// of weights. If the weights are nil, they are assumed to be ones.
+func (self Norm) Compute(data, weights []float64) (result float64) {
+	switch self {
+	case NormMaximum:
+		if weights == nil {
+			return internal.MaxAbsolute(data)
+		}
+		for i, value := range data {
+			result = math.Max(result, math.Abs(weights[i]*value))
+		}
+	case NormSum:
+		if weights == nil {
+			return internal.SumAbsolute(data)
+		}
+		for i, value := range data {
+			result += math.Abs(weights[i] * value)
+		}
+	case NormEuclidean:
+		for i, value := range data {
+			if weights != nil {
+				value *= weights[i]
+			}
+			result += value * value
+		}
+		result = math.Sqrt(result)
+	default:
+		panic("the norm is not supported")
+	}
+	return
+}
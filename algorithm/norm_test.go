@@ -0,0 +1,20 @@
+package algorithm
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestNormCompute(t *testing.T) {
+	data := []float64{3.0, -4.0, 1.0}
+	weights := []float64{1.0, 0.5, -3.0}
+
+	assert.Equal(NormMaximum.Compute(data, nil), 4.0, t)
+	assert.Equal(NormMaximum.Compute(data, weights), 3.0, t)
+	assert.Equal(NormSum.Compute(data, nil), 8.0, t)
+	assert.Equal(NormSum.Compute(data, weights), 8.0, t)
+	assert.Equal(NormEuclidean.Compute(data, nil), math.Sqrt(26.0), t)
+	assert.Equal(NormEuclidean.Compute(data, weights), math.Sqrt(22.0), t)
+}
@@ -0,0 +1,32 @@
+package algorithm
+
+import (
+	"time"
+)
+
+// Observer is notified about the progress of interpolation.
+type Observer interface {
+	// Observe is called after each iteration.
+	Observe(*Progress)
+}
+
+// Reporter is a strategy that reports its internal status.
+type Reporter interface {
+	// Report fills in the strategy-specific fields of a progress report.
+	Report(*Progress)
+}
+
+// Progress contains information about an interpolation iteration.
+type Progress struct {
+	Iteration uint // Iteration number starting from zero
+	Nodes     uint // Number of nodes added in the iteration
+	Total     uint // Number of nodes in the interpolant
+
+	Active    uint      // Number of active level indices
+	Threshold []float64 // Error thresholds of the outputs
+
+	Evaluation time.Duration // Time spent waiting for the target
+	Estimation time.Duration // Time spent on estimation and scoring
+
+	State *State // State of the iteration
+}
@@ -0,0 +1,238 @@
+// Package regression provides least-squares regression of scattered data on
+// sparse grids.
+//
+// Given a set of points and the values observed at them, the surpluses of a
+// sparse-grid interpolant are fitted by minimizing the mean squared residual
+// plus a multiple of the squared norm of the surpluses. The corresponding
+// normal equations are solved by the conjugate gradient method without
+// assembling the system matrix. The grid can also be refined adaptively at the
+// nodes whose supports contain large residuals. The result is an ordinary
+// interpolant, which can be evaluated, for instance, using local.Algorithm.
+package regression
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ready-steady/adapt/algorithm"
+	"github.com/ready-steady/adapt/algorithm/internal"
+	"github.com/ready-steady/adapt/basis"
+	"github.com/ready-steady/adapt/grid"
+)
+
+const (
+	// Iterations is the default maximum number of iterations of the conjugate
+	// gradient method.
+	Iterations = 1000
+	// Tolerance is the default relative tolerance of the conjugate gradient
+	// method.
+	Tolerance = 1e-10
+)
+
+// Regressor is a sparse-grid regressor.
+type Regressor struct {
+	ni uint
+	no uint
+
+	grid   Grid
+	basis  Basis
+	domain *algorithm.Domain
+
+	lmin  uint
+	lmax  uint
+	limit *internal.Limit
+	λ     float64
+	εs    float64
+	nodes uint
+
+	iterations uint
+	εc         float64
+}
+
+// Basis is an interpolation basis.
+type Basis interface {
+	basis.Computer
+	basis.Integrator
+}
+
+// Grid is an interpolation grid.
+type Grid interface {
+	grid.Computer
+	grid.Indexer
+	grid.Refiner
+}
+
+// New creates a regressor. The regularization parameter is the weight of the
+// squared norm of the surpluses relative to the mean squared residual, and the
+// score error is the root-mean-square residual in the support of a node above
+// which the node is refined; see Compute.
+func New(inputs, outputs uint, grid Grid, basis Basis, minLevel, maxLevel uint,
+	regularization, scoreError float64) *Regressor {
+
+	return &Regressor{
+		ni: inputs,
+		no: outputs,
+
+		grid:  grid,
+		basis: basis,
+
+		lmin:  minLevel,
+		lmax:  maxLevel,
+		limit: internal.NewLimit(inputs),
+		λ:     regularization,
+		εs:    scoreError,
+
+		iterations: Iterations,
+		εc:         Tolerance,
+	}
+}
+
+// Restrict sets the domain of the inputs, which is the unit hypercube by
+// default. The points passed to Fit and Compute are then expressed with
+// respect to the domain.
+func (self *Regressor) Restrict(domain *algorithm.Domain) {
+	self.domain = domain
+}
+
+// Limit sets the maximum number of nodes. A zero value means no limit. When
+// the number of nodes is limited, the nodes with the highest scores are refined
+// first, and the refinements that do not fit are skipped.
+func (self *Regressor) Limit(nodes uint) {
+	self.nodes = nodes
+}
+
+// Iterate sets the maximum number of iterations and the relative tolerance of
+// the conjugate gradient method.
+func (self *Regressor) Iterate(iterations uint, tolerance float64) {
+	self.iterations, self.εc = iterations, tolerance
+}
+
+// Fit constructs an interpolant with a given set of indices from a set of
+// points and the values observed at them.
+func (self *Regressor) Fit(indices []uint64, points, values []float64) *algorithm.Surrogate {
+	points = self.prepare(points, values)
+	surpluses := self.solve(indices, nil, points, values)
+	return self.assemble(indices, surpluses)
+}
+
+// Compute constructs an interpolant from a set of points and the values
+// observed at them by refining the grid adaptively. The process starts with
+// the root node and, after each fit, refines the nodes whose scores exceed the
+// score error given at creation, where the score of a node is the maximum over
+// the outputs of the root-mean-square residual of the points in its support
+// weighted by the corresponding basis function. The process stops when no new
+// nodes are produced.
+func (self *Regressor) Compute(points, values []float64) *algorithm.Surrogate {
+	ni := self.ni
+	points = self.prepare(points, values)
+
+	unique := internal.NewUnique(ni)
+	indices := unique.Distil(self.grid.Index(make([]uint64, ni)))
+	surpluses := []float64(nil)
+	for {
+		surpluses = self.solve(indices, surpluses, points, values)
+		scores := self.score(indices, surpluses, points, values)
+		fresh := self.refine(unique, indices, scores)
+		if len(fresh) == 0 {
+			break
+		}
+		indices = append(indices, fresh...)
+	}
+
+	return self.assemble(indices, surpluses)
+}
+
+func (self *Regressor) assemble(indices []uint64, surpluses []float64) *algorithm.Surrogate {
+	ni, no := self.ni, self.no
+	volumes := internal.Measure(self.basis, indices, ni)
+	if self.domain != nil {
+		internal.Scale(volumes, self.domain.Volume())
+	}
+	surrogate := algorithm.NewSurrogate(ni, no)
+	surrogate.Domain = self.domain
+	surrogate.Push(indices, surpluses, volumes)
+	return surrogate
+}
+
+func (self *Regressor) prepare(points, values []float64) []float64 {
+	ni, no := self.ni, self.no
+	np := uint(len(points)) / ni
+	if uint(len(points)) != np*ni || uint(len(values)) != np*no {
+		panic("the number of values does not match the number of points")
+	}
+	if np == 0 {
+		panic("there should be at least one point")
+	}
+	if self.domain != nil {
+		points = self.domain.Backward(points)
+	}
+	return points
+}
+
+func (self *Regressor) refine(unique *internal.Unique, indices []uint64,
+	scores []float64) []uint64 {
+
+	ni := self.ni
+	nn := uint(len(scores))
+
+	order := []uint{}
+	for i := uint(0); i < nn; i++ {
+		index := indices[i*ni : (i+1)*ni]
+		if self.limit.Below(index, self.lmin) ||
+			(scores[i] > self.εs && !self.limit.Reached(index, self.lmax)) {
+
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+
+	fresh := []uint64{}
+	for _, i := range order {
+		children := self.limit.Filter(self.grid.Refine(indices[i*ni:(i+1)*ni]), self.lmax)
+		if self.nodes > 0 {
+			count := unique.Count(children)
+			if nn+uint(len(fresh))/ni+count > self.nodes {
+				continue
+			}
+		}
+		fresh = append(fresh, unique.Distil(children)...)
+	}
+	return fresh
+}
+
+func (self *Regressor) score(indices []uint64, surpluses, points,
+	values []float64) []float64 {
+
+	ni, no := self.ni, self.no
+	nn := uint(len(indices)) / ni
+
+	residuals := internal.Estimate(self.basis, indices, surpluses, points, ni, no)
+	for i := range residuals {
+		residuals[i] = values[i] - residuals[i]
+		residuals[i] *= residuals[i]
+	}
+	errors := internal.Project(self.basis, indices, residuals, points, ni, no)
+	weights := internal.Project(self.basis, indices, repeat(1.0, uint(len(points))/ni),
+		points, ni, 1)
+
+	scores := make([]float64, nn)
+	for i := uint(0); i < nn; i++ {
+		if weights[i] <= 0.0 {
+			continue
+		}
+		for j := uint(0); j < no; j++ {
+			scores[i] = math.Max(scores[i], math.Sqrt(errors[i*no+j]/weights[i]))
+		}
+	}
+	return scores
+}
+
+func repeat(value float64, times uint) []float64 {
+	data := make([]float64, times)
+	for i := range data {
+		data[i] = value
+	}
+	return data
+}
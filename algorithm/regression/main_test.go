@@ -0,0 +1,78 @@
+package regression
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/ready-steady/adapt/algorithm"
+	"github.com/ready-steady/adapt/algorithm/local"
+	"github.com/ready-steady/adapt/basis/polynomial"
+	"github.com/ready-steady/adapt/grid/equidistant"
+	"github.com/ready-steady/assert"
+)
+
+func TestFit(t *testing.T) {
+	const (
+		ni = 2
+		no = 2
+		np = 500
+	)
+
+	grid, basis := equidistant.NewClosed(ni), polynomial.NewClosed(ni, 1)
+	algorithm := local.New(ni, no, grid, basis)
+	expected := algorithm.Compute(func(x, y []float64) {
+		y[0] = math.Sin(3.0*x[0]) * x[1]
+		y[1] = x[0] + x[1]*x[1]
+	}, local.NewStrategy(ni, no, grid, 1, 2, 0.0))
+
+	points := sample(np, ni, 0)
+	values := algorithm.Evaluate(expected, points)
+
+	regressor := New(ni, no, grid, basis, 1, 2, 0.0, 0.0)
+	surrogate := regressor.Fit(expected.Indices, points, values)
+	assert.Equal(surrogate.Indices, expected.Indices, t)
+	assert.Close(surrogate.Surpluses, expected.Surpluses, 1e-8, t)
+	assert.Close(surrogate.Integral, expected.Integral, 1e-8, t)
+}
+
+func TestCompute(t *testing.T) {
+	const (
+		ni = 2
+		no = 1
+		np = 2000
+	)
+
+	target := func(x, y []float64) {
+		y[0] = math.Exp(-10.0 * ((x[0]-1.0)*(x[0]-1.0) + x[1]*x[1]))
+	}
+
+	domain := algorithm.NewDomain([]float64{0.0, -1.0}, []float64{2.0, 1.0})
+	points := domain.Forward(sample(np, ni, 0))
+	values := algorithm.Invoke(target, points, ni, no)
+
+	grid, basis := equidistant.NewClosed(ni), polynomial.NewClosed(ni, 1)
+	regressor := New(ni, no, grid, basis, 1, 6, 1e-6, 1e-2)
+	regressor.Restrict(domain)
+	regressor.Iterate(Iterations, 1e-6)
+	surrogate := regressor.Compute(points, values)
+	assert.Equal(surrogate.Domain, domain, t)
+
+	points = domain.Forward(sample(1000, ni, 1))
+	expected := algorithm.Invoke(target, points, ni, no)
+	actual := local.New(ni, no, grid, basis).Evaluate(surrogate, points)
+	assert.Close(actual, expected, 5e-2, t)
+	assert.Close(surrogate.Integral, []float64{math.Pi / 10.0}, 1e-2, t)
+
+	regressor.Limit(50)
+	assert.Equal(regressor.Compute(points, expected).Nodes <= 50, true, t)
+}
+
+func sample(np, ni uint, seed int64) []float64 {
+	generator := rand.New(rand.NewSource(seed))
+	points := make([]float64, np*ni)
+	for i := range points {
+		points[i] = generator.Float64()
+	}
+	return points
+}
@@ -0,0 +1,91 @@
+package regression
+
+import (
+	"sync"
+
+	"github.com/ready-steady/adapt/algorithm/internal"
+	"github.com/ready-steady/adapt/basis"
+)
+
+// solve fits the surpluses of a set of indices by solving the normal equations
+//
+//	(BᵀB + λ·np·I) α = Bᵀy
+//
+// with the conjugate gradient method preconditioned by the diagonal of the
+// system matrix, where B is the matrix of the basis functions evaluated at the
+// points, which is never assembled. The initial surpluses, if any, correspond
+// to a prefix of the indices.
+func (self *Regressor) solve(indices []uint64, initial, points,
+	values []float64) []float64 {
+
+	ni, no := self.ni, self.no
+	nn := uint(len(indices)) / ni
+	np := uint(len(points)) / ni
+	λ := self.λ * float64(np)
+
+	multiply := func(x []float64) []float64 {
+		y := internal.Project(self.basis, indices,
+			internal.Estimate(self.basis, indices, x, points, ni, no), points, ni, no)
+		for i := range y {
+			y[i] += λ * x[i]
+		}
+		return y
+	}
+
+	diagonal := square(self.basis, indices, points, ni)
+	for i := range diagonal {
+		diagonal[i] += λ
+		if diagonal[i] > 0.0 {
+			diagonal[i] = 1.0 / diagonal[i]
+		}
+	}
+	precondition := func(x []float64) []float64 {
+		y := make([]float64, nn*no)
+		for i := uint(0); i < nn; i++ {
+			for j := uint(0); j < no; j++ {
+				y[i*no+j] = diagonal[i] * x[i*no+j]
+			}
+		}
+		return y
+	}
+
+	x := make([]float64, nn*no)
+	copy(x, initial)
+	b := internal.Project(self.basis, indices, values, points, ni, no)
+
+	return internal.Solve(multiply, precondition, b, x, nn, no, self.iterations, self.εc)
+}
+
+// square computes the sums of the squares of the basis functions evaluated at
+// a set of points, which form the diagonal of BᵀB.
+func square(computer basis.Computer, indices []uint64, points []float64, ni uint) []float64 {
+	nn := uint(len(indices)) / ni
+	np := uint(len(points)) / ni
+	result := make([]float64, nn)
+
+	jobs := make(chan uint, nn)
+	group := sync.WaitGroup{}
+	group.Add(int(nn))
+
+	for i := uint(0); i < internal.Workers; i++ {
+		go func() {
+			for j := range jobs {
+				index := indices[j*ni : (j+1)*ni]
+				for k := uint(0); k < np; k++ {
+					weight := computer.Compute(index, points[k*ni:(k+1)*ni])
+					result[j] += weight * weight
+				}
+				group.Done()
+			}
+		}()
+	}
+
+	for i := uint(0); i < nn; i++ {
+		jobs <- i
+	}
+
+	group.Wait()
+	close(jobs)
+
+	return result
+}
@@ -37,3 +37,18 @@ type Strategy interface {
 	// Score assigns a score to an interpolation element.
 	Score(*Element) float64
 }
+
+// Timer is a strategy whose decisions can depend on the running time.
+type Timer interface {
+	// Timed checks if the running time is limited.
+	Timed() bool
+}
+
+// Resumer is a strategy that can continue the interpolation process from a
+// previously computed interpolant instead of starting over.
+type Resumer interface {
+	// Resume takes into account a state describing all the nodes of an
+	// interpolant, which are grouped by level index, and returns the initial
+	// state of the next iteration.
+	Resume(*State, *Surrogate) *State
+}
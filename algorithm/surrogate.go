@@ -2,6 +2,7 @@ package algorithm
 
 import (
 	"fmt"
+	"math"
 )
 
 // Surrogate is an interpolant for a function.
@@ -13,6 +14,21 @@ type Surrogate struct {
 	Indices   []uint64  // Indices of the nodes
 	Surpluses []float64 // Hierarchical surpluses
 	Integral  []float64 // Integral over the whole domain
+
+	Domain *Domain // Domain of the inputs (nil for the unit hypercube)
+
+	History []Iteration // Convergence history (nil if not recorded)
+}
+
+// Iteration contains summary information about an interpolation iteration.
+type Iteration struct {
+	Nodes  uint // Number of nodes added in the iteration
+	Total  uint // Number of nodes after the iteration
+	Active uint // Number of active level indices after the iteration
+
+	Maximum  []float64 // Maximum absolute surplus of each output
+	Mean     []float64 // Mean absolute surplus of each output
+	Integral []float64 // Integral after the iteration
 }
 
 // NewSurrogate returns an empty surrogate.
@@ -35,6 +51,35 @@ func (self *Surrogate) Push(indices []uint64, surpluses, volumes []float64) {
 	cumulate(indices, surpluses, volumes, self.Inputs, self.Outputs, self.Integral)
 }
 
+// Track appends an entry to the convergence history given the surpluses of the
+// last iteration, which should already be pushed.
+func (self *Surrogate) Track(surpluses []float64, active uint) {
+	no := self.Outputs
+	nn := uint(len(surpluses)) / no
+	iteration := Iteration{
+		Nodes:  nn,
+		Total:  self.Nodes,
+		Active: active,
+
+		Maximum:  make([]float64, no),
+		Mean:     make([]float64, no),
+		Integral: append([]float64(nil), self.Integral...),
+	}
+	for i := uint(0); i < nn; i++ {
+		for j := uint(0); j < no; j++ {
+			surplus := math.Abs(surpluses[i*no+j])
+			iteration.Maximum[j] = math.Max(iteration.Maximum[j], surplus)
+			iteration.Mean[j] += surplus
+		}
+	}
+	if nn > 0 {
+		for j := uint(0); j < no; j++ {
+			iteration.Mean[j] /= float64(nn)
+		}
+	}
+	self.History = append(self.History, iteration)
+}
+
 // String returns a summary.
 func (self *Surrogate) String() string {
 	phantom := struct {
@@ -1,6 +1,8 @@
 package algorithm
 
 import (
+	"context"
+	"fmt"
 	"sync"
 
 	"github.com/ready-steady/adapt/algorithm/internal"
@@ -9,6 +11,10 @@ import (
 // Target is a function to be interpolated.
 type Target func([]float64, []float64)
 
+// ContextTarget is a function to be interpolated that can fail and be
+// cancelled.
+type ContextTarget func(context.Context, []float64, []float64) error
+
 // Invoke evaluates a function at multiple points using multiple goroutines.
 func Invoke(target Target, points []float64, ni, no uint) []float64 {
 	np := uint(len(points)) / ni
@@ -37,3 +43,71 @@ func Invoke(target Target, points []float64, ni, no uint) []float64 {
 
 	return values
 }
+
+// InvokeContext evaluates a function at multiple points using multiple
+// goroutines. The evaluation stops at the first failure or once the context is
+// cancelled, in which case the corresponding error is returned. A panic in the
+// function is also reported as an error.
+func InvokeContext(ctx context.Context, target ContextTarget, points []float64,
+	ni, no uint) ([]float64, error) {
+
+	np := uint(len(points)) / ni
+
+	values := make([]float64, np*no)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	failure, once := error(nil), sync.Once{}
+	fail := func(err error) {
+		once.Do(func() {
+			failure = err
+			cancel()
+		})
+	}
+
+	jobs := make(chan uint)
+	group := sync.WaitGroup{}
+	group.Add(int(internal.Workers))
+
+	for i := uint(0); i < internal.Workers; i++ {
+		go func() {
+			defer group.Done()
+			for j := range jobs {
+				if err := call(ctx, target, points[j*ni:(j+1)*ni], values[j*no:(j+1)*no]); err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+outer:
+	for i := uint(0); i < np; i++ {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break outer
+		}
+	}
+
+	close(jobs)
+	group.Wait()
+
+	if failure != nil {
+		return nil, failure
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+func call(ctx context.Context, target ContextTarget, point, value []float64) (err error) {
+	defer func() {
+		if reason := recover(); reason != nil {
+			err = fmt.Errorf("the target panicked: %v", reason)
+		}
+	}()
+	return target(ctx, point, value)
+}
@@ -0,0 +1,50 @@
+package algorithm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestInvokeContext(t *testing.T) {
+	points := []float64{0.0, 0.25, 0.5, 0.75, 1.0}
+	failure := errors.New("failure")
+
+	values, err := InvokeContext(context.Background(), func(_ context.Context,
+		x, y []float64) error {
+
+		y[0], y[1] = x[0], 2.0*x[0]
+		return nil
+	}, points, 1, 2)
+	assert.Equal(err, nil, t)
+	assert.Equal(values, []float64{0.0, 0.0, 0.25, 0.5, 0.5, 1.0, 0.75, 1.5, 1.0, 2.0}, t)
+
+	_, err = InvokeContext(context.Background(), func(_ context.Context,
+		x, _ []float64) error {
+
+		if x[0] == 0.5 {
+			return failure
+		}
+		return nil
+	}, points, 1, 1)
+	assert.Equal(err, failure, t)
+
+	_, err = InvokeContext(context.Background(), func(_ context.Context,
+		x, _ []float64) error {
+
+		if x[0] == 0.5 {
+			panic("failure")
+		}
+		return nil
+	}, points, 1, 1)
+	assert.Equal(err != nil, true, t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = InvokeContext(ctx, func(ctx context.Context, _, _ []float64) error {
+		return ctx.Err()
+	}, points, 1, 1)
+	assert.Equal(err, context.Canceled, t)
+}
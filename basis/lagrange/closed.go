@@ -0,0 +1,183 @@
+package lagrange
+
+import (
+	"math"
+	"sync"
+
+	"github.com/ready-steady/adapt/grid/chebyshev"
+)
+
+// Closed is a basis in [0, 1]^n.
+//
+// The basis functions are hierarchical global Lagrange polynomials on the
+// nested Clenshaw–Curtis nodes of chebyshev.Closed. A basis function of level
+// l is the Lagrange polynomial of degree 2^l that is one at its own node and
+// zero at all other nodes of levels 0, 1, …, l.
+type Closed struct {
+	nd   uint
+	grid chebyshev.Closed
+
+	cache map[uint64]*rule
+	mutex sync.RWMutex
+}
+
+type rule struct {
+	x []float64 // Nodes
+	v []float64 // Barycentric weights
+	w []float64 // Quadrature weights
+}
+
+// NewClosed creates a basis.
+func NewClosed(dimensions uint) *Closed {
+	return &Closed{
+		nd:    dimensions,
+		grid:  *chebyshev.NewClosed(1),
+		cache: make(map[uint64]*rule),
+	}
+}
+
+// Compute evaluates a basis function.
+func (self *Closed) Compute(index []uint64, point []float64) float64 {
+	return compute(index, point, self.nd, self.compute)
+}
+
+// Integrate computes the integral of a basis function.
+func (self *Closed) Integrate(index []uint64) float64 {
+	return integrate(index, self.nd, self.integrate)
+}
+
+// IntegrateProduct computes the integral of a product of basis functions.
+func (self *Closed) IntegrateProduct(indices ...[]uint64) float64 {
+	return integrateProduct(indices, self.nd, self.integrateProduct)
+}
+
+// Support computes the support of a basis function, which is the whole domain.
+func (self *Closed) Support(_ []uint64) ([]float64, []float64) {
+	lower, upper := make([]float64, self.nd), make([]float64, self.nd)
+	for i := range upper {
+		upper[i] = 1.0
+	}
+	return lower, upper
+}
+
+func (self *Closed) compute(level, order uint64, x float64) float64 {
+	if level == 0 {
+		return 1.0
+	}
+
+	rule := self.rule(level)
+
+	// Use the second (true) barycentric formula.
+	numerator, denominator := 0.0, 0.0
+	for i := range rule.x {
+		Δ := x - rule.x[i]
+		if Δ == 0.0 {
+			if uint64(i) == order {
+				return 1.0
+			} else {
+				return 0.0
+			}
+		}
+		term := rule.v[i] / Δ
+		if uint64(i) == order {
+			numerator = term
+		}
+		denominator += term
+	}
+
+	return numerator / denominator
+}
+
+func (self *Closed) integrate(level, order uint64) float64 {
+	if level == 0 {
+		return 1.0
+	}
+	return self.rule(level).w[order]
+}
+
+func (self *Closed) integrateProduct(levels, orders []uint64) float64 {
+	degree := uint64(0)
+	for _, level := range levels {
+		if level > 0 {
+			degree += uint64(1) << level
+		}
+	}
+	if degree == 0 {
+		return 1.0
+	}
+
+	// Use a Clenshaw–Curtis quadrature rule to integrate. Such a rule with n + 1
+	// nodes integrates exactly polynomials up to order n.
+	level := uint64(1)
+	for uint64(1)<<level < degree {
+		level++
+	}
+	rule := self.rule(level)
+
+	value := 0.0
+	for i := range rule.x {
+		term := rule.w[i]
+		for j := range levels {
+			term *= self.compute(levels[j], orders[j], rule.x[i])
+		}
+		value += term
+	}
+	return value
+}
+
+func (self *Closed) rule(level uint64) *rule {
+	self.mutex.RLock()
+	rule, ok := self.cache[level]
+	self.mutex.RUnlock()
+	if ok {
+		return rule
+	}
+
+	rule = newRule(level, &self.grid)
+
+	self.mutex.Lock()
+	self.cache[level] = rule
+	self.mutex.Unlock()
+
+	return rule
+}
+
+func newRule(level uint64, grid *chebyshev.Closed) *rule {
+	n := uint64(1) << level
+
+	x := make([]float64, n+1)
+	v := make([]float64, n+1)
+	w := make([]float64, n+1)
+
+	for i := uint64(0); i <= n; i++ {
+		x[i] = grid.Node(level, i)
+
+		// The barycentric weights of the Chebyshev–Gauss–Lobatto nodes are
+		// alternating ones, which are halved at the endpoints.
+		v[i] = 1.0
+		if i%2 == 1 {
+			v[i] = -1.0
+		}
+		if i == 0 || i == n {
+			v[i] /= 2.0
+		}
+
+		// The weights of the Clenshaw–Curtis quadrature rule scaled to [0, 1].
+		θ := math.Pi * float64(i) / float64(n)
+		sum := 0.0
+		for j := uint64(1); j <= n/2; j++ {
+			b := 2.0
+			if 2*j == n {
+				b = 1.0
+			}
+			sum += b / float64(4*j*j-1) * math.Cos(2.0*float64(j)*θ)
+		}
+		c := 2.0
+		if i == 0 || i == n {
+			c = 1.0
+		}
+		w[i] = c / float64(n) * (1.0 - sum) / 2.0
+	}
+
+	return &rule{x, v, w}
+}
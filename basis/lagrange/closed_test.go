@@ -0,0 +1,132 @@
+package lagrange
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/adapt/grid/chebyshev"
+	"github.com/ready-steady/adapt/internal"
+	"github.com/ready-steady/assert"
+)
+
+func TestClosedCompute(t *testing.T) {
+	const (
+		maxLevel = 4
+	)
+
+	basis := NewClosed(1)
+	grid := chebyshev.NewClosed(1)
+
+	compute := func(level, order uint64, point float64) float64 {
+		return basis.Compute(internal.Compose([]uint64{level}, []uint64{order}), []float64{point})
+	}
+
+	for level := uint64(1); level <= maxLevel; level++ {
+		for order := uint64(0); order <= uint64(1)<<level; order++ {
+			for other := uint64(0); other <= uint64(1)<<level; other++ {
+				value := compute(level, order, grid.Node(level, other))
+				if other == order {
+					assert.Equal(value, 1.0, t)
+				} else {
+					assert.Equal(value, 0.0, t)
+				}
+			}
+		}
+	}
+
+	level, order, x := uint64(2), uint64(1), 0.3
+	expected := 1.0
+	for i := uint64(0); i <= 4; i++ {
+		if i != order {
+			expected *= (x - grid.Node(level, i)) / (grid.Node(level, order) - grid.Node(level, i))
+		}
+	}
+	assert.Close(compute(level, order, x), expected, 1e-15, t)
+}
+
+func TestClosedIntegrate(t *testing.T) {
+	basis := NewClosed(1)
+
+	levels := []uint64{0, 1, 1, 2, 2}
+	orders := []uint64{0, 0, 2, 1, 3}
+	values := []float64{1.0, 1.0 / 6.0, 1.0 / 6.0, 4.0 / 15.0, 4.0 / 15.0}
+
+	for i := range levels {
+		indices := internal.Compose([]uint64{levels[i]}, []uint64{orders[i]})
+		assert.Close(basis.Integrate(indices), values[i], 1e-15, t)
+	}
+}
+
+func TestClosedInterpolate(t *testing.T) {
+	const (
+		maxLevel = 3
+	)
+
+	basis := NewClosed(1)
+	grid := chebyshev.NewClosed(1)
+
+	target := func(x float64) float64 {
+		return math.Pow(x, 8.0) - 2.0*math.Pow(x, 3.0) + 1.0
+	}
+
+	indices := []uint64{0}
+	for level := uint64(1); level <= maxLevel; level++ {
+		for order := uint64(0); order <= uint64(1)<<level; order++ {
+			if level == 1 || order%2 == 1 {
+				indices = append(indices, level|order<<internal.LEVEL_SIZE)
+			}
+		}
+	}
+
+	nodes := grid.Compute(indices)
+
+	evaluate := func(surpluses []float64, x float64) float64 {
+		value := 0.0
+		for i := range surpluses {
+			value += surpluses[i] * basis.Compute(indices[i:i+1], []float64{x})
+		}
+		return value
+	}
+
+	surpluses := make([]float64, 0, len(indices))
+	for i := range indices {
+		surpluses = append(surpluses, target(nodes[i])-evaluate(surpluses, nodes[i]))
+	}
+
+	for _, x := range []float64{0.0, 0.1, 0.33, 0.5, 0.72, 0.9, 1.0} {
+		assert.Close(evaluate(surpluses, x), target(x), 1e-13, t)
+	}
+
+	integral := 0.0
+	for i := range indices {
+		integral += surpluses[i] * basis.Integrate(indices[i:i+1])
+	}
+	assert.Close(integral, 1.0/9.0-2.0/4.0+1.0, 1e-14, t)
+}
+
+func TestClosedIntegrateProduct(t *testing.T) {
+	const (
+		nn = 100000
+	)
+
+	basis := NewClosed(1)
+
+	indices := internal.Compose(
+		[]uint64{0, 1, 2, 2, 3},
+		[]uint64{0, 2, 1, 3, 5},
+	)
+
+	for i := range indices {
+		for j := i; j < len(indices); j++ {
+			one, two := indices[i:i+1], indices[j:j+1]
+			value := 0.0
+			for k := 0; k < nn; k++ {
+				x := []float64{(float64(k) + 0.5) / nn}
+				value += basis.Compute(one, x) * basis.Compute(two, x)
+			}
+			assert.Close(basis.IntegrateProduct(one, two), value/nn, 1e-8, t)
+		}
+		assert.Close(basis.IntegrateProduct(indices[i:i+1]),
+			basis.Integrate(indices[i:i+1]), 1e-15, t)
+	}
+}
@@ -0,0 +1,2 @@
+// Package lagrange provides global Lagrange interpolation bases.
+package lagrange
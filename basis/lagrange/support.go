@@ -0,0 +1,44 @@
+package lagrange
+
+import (
+	"github.com/ready-steady/adapt/internal"
+)
+
+func compute(index []uint64, point []float64, nd uint,
+	compute func(uint64, uint64, float64) float64) float64 {
+
+	value := 1.0
+	for i := uint(0); i < nd && value != 0.0; i++ {
+		value *= compute(index[i]&internal.LEVEL_MASK,
+			index[i]>>internal.LEVEL_SIZE, point[i])
+	}
+	return value
+}
+
+func integrate(index []uint64, nd uint,
+	integrate func(uint64, uint64) float64) float64 {
+
+	value := 1.0
+	for i := uint(0); i < nd && value != 0.0; i++ {
+		value *= integrate(index[i]&internal.LEVEL_MASK,
+			index[i]>>internal.LEVEL_SIZE)
+	}
+	return value
+}
+
+func integrateProduct(indices [][]uint64, nd uint,
+	integrate func([]uint64, []uint64) float64) float64 {
+
+	nf := len(indices)
+	levels, orders := make([]uint64, nf), make([]uint64, nf)
+
+	value := 1.0
+	for i := uint(0); i < nd && value != 0.0; i++ {
+		for j := range indices {
+			levels[j] = indices[j][i] & internal.LEVEL_MASK
+			orders[j] = indices[j][i] >> internal.LEVEL_SIZE
+		}
+		value *= integrate(levels, orders)
+	}
+	return value
+}
@@ -10,3 +10,24 @@ type Computer interface {
 type Integrator interface {
 	Integrate([]uint64) float64
 }
+
+// IntegratorProduct returns the integral of a product of basis functions.
+type IntegratorProduct interface {
+	IntegrateProduct(...[]uint64) float64
+}
+
+// Supporter returns the support of a basis function, which is a box given by
+// its lower and upper corners outside of which the function is zero.
+type Supporter interface {
+	Support([]uint64) ([]float64, []float64)
+}
+
+// Differentiator returns the first and second partial derivatives of a basis
+// function with respect to one and two dimensions, respectively.
+// Where a derivative is discontinuous, the average of the one-sided derivatives
+// is returned, except on the boundary of the unit hypercube, where the
+// one-sided derivative from the inside is returned.
+type Differentiator interface {
+	Differentiate([]uint64, []float64, uint) float64
+	DifferentiateTwice([]uint64, []float64, uint, uint) float64
+}
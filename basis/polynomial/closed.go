@@ -27,11 +27,43 @@ func (self *Closed) Compute(index []uint64, point []float64) float64 {
 	return compute(index, point, self.nd, self.compute)
 }
 
+// Differentiate computes the first partial derivative of a basis function with
+// respect to a dimension.
+func (self *Closed) Differentiate(index []uint64, point []float64, i uint) float64 {
+	orders := make([]uint, self.nd)
+	orders[i]++
+	return differentiate(index, point, self.nd, orders, self.derive)
+}
+
+// DifferentiateTwice computes the second partial derivative of a basis
+// function with respect to two dimensions.
+func (self *Closed) DifferentiateTwice(index []uint64, point []float64, i, j uint) float64 {
+	orders := make([]uint, self.nd)
+	orders[i]++
+	orders[j]++
+	return differentiate(index, point, self.nd, orders, self.derive)
+}
+
 // Integrate computes the integral of a basis function.
 func (self *Closed) Integrate(index []uint64) float64 {
 	return integrate(index, self.nd, self.integrate)
 }
 
+// IntegrateProduct computes the integral of a product of basis functions.
+func (self *Closed) IntegrateProduct(indices ...[]uint64) float64 {
+	return integrateProduct(indices, self.nd, self.integrateProduct)
+}
+
+// Support computes the support of a basis function.
+func (self *Closed) Support(index []uint64) ([]float64, []float64) {
+	return support(index, self.nd, self.support)
+}
+
+// Power returns the order of the polynomials.
+func (self *Closed) Power() uint {
+	return self.np
+}
+
 func (self *Closed) compute(level, order uint64, x float64) float64 {
 	np := self.np
 	if level < uint64(np) {
@@ -82,6 +114,59 @@ func (self *Closed) compute(level, order uint64, x float64) float64 {
 	return value
 }
 
+// derive computes a derivative of a one-dimensional basis function. Where the
+// derivatives are discontinuous, which is at the endpoints of the support and,
+// for the piecewise-linear functions, at the node, the average of the
+// one-sided derivatives is taken; see weigh and linear.
+func (self *Closed) derive(level, order uint64, x float64, k uint) float64 {
+	np := self.np
+	if level < uint64(np) {
+		np = uint(level)
+	}
+	if np == 0 {
+		if k == 0 {
+			return 1.0
+		}
+		return 0.0
+	}
+
+	xi, h, _ := self.grid.Node(level, order)
+
+	weight := weigh(x, xi-h, xi+h)
+	if weight == 0.0 {
+		return 0.0
+	}
+
+	if np == 1 {
+		return weight * linear(x, xi, h, k)
+	}
+
+	value := newProduct()
+
+	// The left endpoint of the local support.
+	xl := xi - h
+	value.multiply(x, xi, xl)
+	np -= 1
+
+	// The right endpoint of the local support.
+	xr := xi + h
+	value.multiply(x, xi, xr)
+	np -= 1
+
+	// Find the rest of the needed ancestors.
+	for np > 0 {
+		level, order = self.grid.Parent(level, order)
+		xj, _, _ := self.grid.Node(level, order)
+		if equal(xj, xl) || equal(xj, xr) {
+			continue
+		}
+		value.multiply(x, xi, xj)
+		np -= 1
+	}
+
+	return weight * value[k]
+}
+
 func (self *Closed) integrate(level, order uint64) float64 {
 	np := self.np
 	if level < uint64(np) {
@@ -110,3 +195,36 @@ func (self *Closed) integrate(level, order uint64) float64 {
 		return self.compute(level, order, x)
 	})
 }
+
+func (self *Closed) support(level, order uint64) (float64, float64) {
+	if level == 0 || self.np == 0 {
+		return 0.0, 1.0
+	}
+	x, h, _ := self.grid.Node(level, order)
+	return math.Max(0.0, x-h), math.Min(1.0, x+h)
+}
+
+func (self *Closed) integrateProduct(levels, orders []uint64) float64 {
+	a, b, degree := 0.0, 1.0, uint(0)
+	breakpoints := make([]float64, 0, len(levels))
+	for i := range levels {
+		np := self.np
+		if levels[i] < uint64(np) {
+			np = uint(levels[i])
+		}
+		if np == 0 {
+			continue
+		}
+		x, h, _ := self.grid.Node(levels[i], orders[i])
+		a, b = math.Max(a, x-h), math.Min(b, x+h)
+		breakpoints = append(breakpoints, x)
+		degree += np
+	}
+	return piecewise(a, b, breakpoints, degree, func(x float64) float64 {
+		value := 1.0
+		for i := range levels {
+			value *= self.compute(levels[i], orders[i], x)
+		}
+		return value
+	})
+}
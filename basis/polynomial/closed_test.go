@@ -116,6 +116,38 @@ func TestClosedCompute1D3P(t *testing.T) {
 	}, 1e-15, t)
 }
 
+func TestClosedDifferentiate(t *testing.T) {
+	const (
+		nd = 2
+		ns = 40
+	)
+
+	indices := generateIndices(nd, ns, equidistant.NewClosed(nd).Refine)
+	points := []float64{0.1, 0.37, 0.37, 0.61, 0.61, 0.83, 0.83, 0.1, 0.45, 0.55}
+
+	for np := uint(1); np <= 4; np++ {
+		checkDerivatives(NewClosed(nd, np), nd, indices, points, t)
+	}
+
+	points = []float64{0.0, 0.5, 0.25, 1.0, 1.0, 0.0, 0.75, 0.125, 0.5, 0.375}
+	for np := uint(1); np <= 4; np++ {
+		checkDerivativesAtNodes(NewClosed(nd, np), nd, indices, points, t)
+	}
+}
+
+func TestClosedSupport(t *testing.T) {
+	const (
+		nd = 2
+		ns = 40
+	)
+
+	grid := equidistant.NewClosed(nd)
+	indices := generateIndices(nd, ns, grid.Refine)
+	for np := uint(1); np <= 4; np++ {
+		checkSupport(NewClosed(nd, np), grid, nd, indices, t)
+	}
+}
+
 func TestClosedIntegrate(t *testing.T) {
 	basis := NewClosed(1, 1)
 
@@ -146,3 +178,26 @@ func benchmarkClosedCompute(power uint, b *testing.B) {
 		}
 	}
 }
+
+func TestClosedIntegrateProduct(t *testing.T) {
+	const (
+		nd = 2
+		nn = 1000
+	)
+
+	indices := internal.Compose(
+		[]uint64{0, 0, 1, 2, 2, 1, 3, 3},
+		[]uint64{0, 0, 0, 1, 1, 2, 3, 5},
+	)
+
+	for np := uint(1); np <= 3; np++ {
+		basis := NewClosed(nd, np)
+		for _, pair := range [][2]uint{{0, 1}, {1, 2}, {2, 3}, {1, 3}, {3, 3}} {
+			one, two := indices[pair[0]*nd:(pair[0]+1)*nd], indices[pair[1]*nd:(pair[1]+1)*nd]
+			assert.Close(basis.IntegrateProduct(one, two),
+				integrateNumerically(basis, nd, nn, one, two), 1e-6, t)
+		}
+		one := indices[1*nd : 2*nd]
+		assert.Close(basis.IntegrateProduct(one), basis.Integrate(one), 1e-15, t)
+	}
+}
@@ -9,16 +9,15 @@ import (
 // Open is a basis in (0, 1)^n.
 type Open struct {
 	nd   uint
+	np   uint
 	grid equidistant.Open
 }
 
 // NewOpen creates a basis.
 func NewOpen(dimensions, power uint) *Open {
-	if power != 1 {
-		panic("not implemented")
-	}
 	return &Open{
 		nd:   dimensions,
+		np:   power,
 		grid: *equidistant.NewOpen(1),
 	}
 }
@@ -28,46 +27,302 @@ func (self *Open) Compute(index []uint64, point []float64) float64 {
 	return compute(index, point, self.nd, self.compute)
 }
 
+// Differentiate computes the first partial derivative of a basis function with
+// respect to a dimension.
+func (self *Open) Differentiate(index []uint64, point []float64, i uint) float64 {
+	orders := make([]uint, self.nd)
+	orders[i]++
+	return differentiate(index, point, self.nd, orders, self.derive)
+}
+
+// DifferentiateTwice computes the second partial derivative of a basis
+// function with respect to two dimensions.
+func (self *Open) DifferentiateTwice(index []uint64, point []float64, i, j uint) float64 {
+	orders := make([]uint, self.nd)
+	orders[i]++
+	orders[j]++
+	return differentiate(index, point, self.nd, orders, self.derive)
+}
+
 // Integrate computes the integral of a basis function.
 func (self *Open) Integrate(index []uint64) float64 {
 	return integrate(index, self.nd, self.integrate)
 }
 
+// IntegrateProduct computes the integral of a product of basis functions.
+func (self *Open) IntegrateProduct(indices ...[]uint64) float64 {
+	return integrateProduct(indices, self.nd, self.integrateProduct)
+}
+
+// Support computes the support of a basis function.
+func (self *Open) Support(index []uint64) ([]float64, []float64) {
+	return support(index, self.nd, self.support)
+}
+
+// Power returns the order of the polynomials.
+func (self *Open) Power() uint {
+	return self.np
+}
+
 func (self *Open) compute(level, order uint64, x float64) float64 {
-	if level == 0 {
+	np := self.np
+	if level < uint64(np) {
+		np = uint(level)
+	}
+	if np == 0 {
 		return 1.0
 	}
+
 	xi, h, n := self.grid.Node(level, order)
+
 	switch order {
 	case 0:
 		if x >= 2.0*h {
 			return 0.0
 		}
-		return 2.0 - x/h
+		if np == 1 {
+			return 2.0 - x/h
+		}
+		return self.extrapolate(level, order, np, xi, x)
 	case n - 1:
 		left := float64(n - 1)
 		if x <= left*h {
 			return 0.0
 		}
-		return x/h - left
-	default:
-		Δ := math.Abs(x - xi)
-		if Δ >= h {
-			return 0.0
+		if np == 1 {
+			return x/h - left
 		}
+		return self.extrapolate(level, order, np, xi, x)
+	}
+
+	Δ := math.Abs(x - xi)
+	if Δ >= h {
+		return 0.0
+	}
+
+	if np == 1 {
 		return 1.0 - Δ/h
 	}
+
+	value := 1.0
+
+	// The left endpoint of the local support.
+	xl := xi - h
+	value *= (x - xl) / (xi - xl)
+	np -= 1
+
+	// The right endpoint of the local support.
+	xr := xi + h
+	value *= (x - xr) / (xi - xr)
+	np -= 1
+
+	// Find the rest of the needed ancestors.
+	for np > 0 {
+		level, order = self.grid.Parent(level, order)
+		xj, _, _ := self.grid.Node(level, order)
+		if equal(xj, xl) || equal(xj, xr) {
+			continue
+		}
+		value *= (x - xj) / (xi - xj)
+		np -= 1
+	}
+
+	return value
+}
+
+// derive computes a derivative of a one-dimensional basis function. Where the
+// derivatives are discontinuous, which is at the endpoints of the support and,
+// for the piecewise-linear functions, at the node, the average of the
+// one-sided derivatives is taken; see weigh and linear.
+func (self *Open) derive(level, order uint64, x float64, k uint) float64 {
+	np := self.np
+	if level < uint64(np) {
+		np = uint(level)
+	}
+	if np == 0 {
+		if k == 0 {
+			return 1.0
+		}
+		return 0.0
+	}
+
+	xi, h, n := self.grid.Node(level, order)
+
+	switch order {
+	case 0:
+		weight := weigh(x, 0.0, 2.0*h)
+		if weight == 0.0 {
+			return 0.0
+		}
+		if np == 1 {
+			switch k {
+			case 0:
+				return 2.0 - x/h
+			case 1:
+				return -weight / h
+			}
+			return 0.0
+		}
+		return weight * self.deriveExtrapolated(level, order, np, xi, x, k)
+	case n - 1:
+		left := float64(n - 1)
+		weight := weigh(x, left*h, 1.0)
+		if weight == 0.0 {
+			return 0.0
+		}
+		if np == 1 {
+			switch k {
+			case 0:
+				return x/h - left
+			case 1:
+				return weight / h
+			}
+			return 0.0
+		}
+		return weight * self.deriveExtrapolated(level, order, np, xi, x, k)
+	}
+
+	weight := weigh(x, xi-h, xi+h)
+	if weight == 0.0 {
+		return 0.0
+	}
+
+	if np == 1 {
+		return weight * linear(x, xi, h, k)
+	}
+
+	value := newProduct()
+
+	// The left endpoint of the local support.
+	xl := xi - h
+	value.multiply(x, xi, xl)
+	np -= 1
+
+	// The right endpoint of the local support.
+	xr := xi + h
+	value.multiply(x, xi, xr)
+	np -= 1
+
+	// Find the rest of the needed ancestors.
+	for np > 0 {
+		level, order = self.grid.Parent(level, order)
+		xj, _, _ := self.grid.Node(level, order)
+		if equal(xj, xl) || equal(xj, xr) {
+			continue
+		}
+		value.multiply(x, xi, xj)
+		np -= 1
+	}
+
+	return weight * value[k]
 }
 
 func (self *Open) integrate(level, order uint64) float64 {
-	if level == 0 {
+	np := self.np
+	if level < uint64(np) {
+		np = uint(level)
+	}
+	if np == 0 {
 		return 1.0
 	}
-	_, h, n := self.grid.Node(level, order)
+
+	x, h, n := self.grid.Node(level, order)
+
+	if np == 1 {
+		switch order {
+		case 0, n - 1:
+			return 2.0 * h
+		default:
+			return 1.0 * h
+		}
+	}
+
+	a, b := x-h, x+h
+	switch order {
+	case 0:
+		a, b = 0.0, 2.0*h
+	case n - 1:
+		a, b = 1.0-2.0*h, 1.0
+	}
+
+	// Use a Gauss–Legendre quadrature rule to integrate. See the corresponding
+	// comment in Closed.integrate.
+	nodes := uint(math.Ceil((float64(np) + 1.0) / 2.0))
+	return quadrature(a, b, nodes, func(x float64) float64 {
+		return self.compute(level, order, x)
+	})
+}
+
+func (self *Open) support(level, order uint64) (float64, float64) {
+	if level == 0 || self.np == 0 {
+		return 0.0, 1.0
+	}
+	x, h, n := self.grid.Node(level, order)
 	switch order {
-	case 0, n - 1:
-		return 2.0 * h
-	default:
-		return 1.0 * h
+	case 0:
+		return 0.0, 2.0 * h
+	case n - 1:
+		return 1.0 - 2.0*h, 1.0
+	}
+	return x - h, x + h
+}
+
+func (self *Open) integrateProduct(levels, orders []uint64) float64 {
+	a, b, degree := 0.0, 1.0, uint(0)
+	breakpoints := make([]float64, 0, len(levels))
+	for i := range levels {
+		np := self.np
+		if levels[i] < uint64(np) {
+			np = uint(levels[i])
+		}
+		if np == 0 {
+			continue
+		}
+		x, h, n := self.grid.Node(levels[i], orders[i])
+		switch orders[i] {
+		case 0:
+			b = math.Min(b, 2.0*h)
+		case n - 1:
+			a = math.Max(a, 1.0-2.0*h)
+		default:
+			a, b = math.Max(a, x-h), math.Min(b, x+h)
+		}
+		breakpoints = append(breakpoints, x)
+		degree += np
+	}
+	return piecewise(a, b, breakpoints, degree, func(x float64) float64 {
+		value := 1.0
+		for i := range levels {
+			value *= self.compute(levels[i], orders[i], x)
+		}
+		return value
+	})
+}
+
+// extrapolate evaluates a basis function of the first or last order, in which
+// case all the needed ancestors are on the same side of the node.
+func (self *Open) extrapolate(level, order uint64, np uint, xi, x float64) float64 {
+	value := 1.0
+	for np > 0 {
+		level, order = self.grid.Parent(level, order)
+		xj, _, _ := self.grid.Node(level, order)
+		value *= (x - xj) / (xi - xj)
+		np -= 1
+	}
+	return value
+}
+
+// deriveExtrapolated computes a derivative of a basis function of the first or
+// last order; see extrapolate.
+func (self *Open) deriveExtrapolated(level, order uint64, np uint, xi, x float64,
+	k uint) float64 {
+
+	value := newProduct()
+	for np > 0 {
+		level, order = self.grid.Parent(level, order)
+		xj, _, _ := self.grid.Node(level, order)
+		value.multiply(x, xi, xj)
+		np -= 1
 	}
+	return value[k]
 }
@@ -12,6 +12,14 @@ func BenchmarkOpenCompute1(b *testing.B) {
 	benchmarkOpenCompute(1, b)
 }
 
+func BenchmarkOpenCompute2(b *testing.B) {
+	benchmarkOpenCompute(2, b)
+}
+
+func BenchmarkOpenCompute3(b *testing.B) {
+	benchmarkOpenCompute(3, b)
+}
+
 func benchmarkOpenCompute(power uint, b *testing.B) {
 	const (
 		nd = 10
@@ -120,6 +128,38 @@ func TestOpenCompute(t *testing.T) {
 	}
 }
 
+func TestOpenDifferentiate(t *testing.T) {
+	const (
+		nd = 2
+		ns = 40
+	)
+
+	indices := generateIndices(nd, ns, equidistant.NewOpen(nd).Refine)
+	points := []float64{0.1, 0.37, 0.37, 0.61, 0.61, 0.83, 0.83, 0.1, 0.45, 0.55}
+
+	for np := uint(1); np <= 4; np++ {
+		checkDerivatives(NewOpen(nd, np), nd, indices, points, t)
+	}
+
+	points = []float64{0.0, 0.5, 0.25, 1.0, 1.0, 0.0, 0.75, 0.125, 0.5, 0.375}
+	for np := uint(1); np <= 4; np++ {
+		checkDerivativesAtNodes(NewOpen(nd, np), nd, indices, points, t)
+	}
+}
+
+func TestOpenSupport(t *testing.T) {
+	const (
+		nd = 2
+		ns = 40
+	)
+
+	grid := equidistant.NewOpen(nd)
+	indices := generateIndices(nd, ns, grid.Refine)
+	for np := uint(1); np <= 4; np++ {
+		checkSupport(NewOpen(nd, np), grid, nd, indices, t)
+	}
+}
+
 func TestOpenIntegrate(t *testing.T) {
 	basis := NewOpen(1, 1)
 
@@ -132,3 +172,97 @@ func TestOpenIntegrate(t *testing.T) {
 		assert.Equal(basis.Integrate(indices), values[i], t)
 	}
 }
+
+func TestOpenComputeHigherPower(t *testing.T) {
+	const (
+		nd = 1
+		nl = 5
+	)
+
+	grid := equidistant.NewOpen(nd)
+
+	indices := generateIndices(nd, 1<<nl-1, grid.Refine)
+	nodes := grid.Compute(indices)
+
+	for np := uint(2); np <= 4; np++ {
+		basis := NewOpen(nd, np)
+
+		target := func(x float64) float64 {
+			value := 1.0
+			for i := uint(0); i < np; i++ {
+				value = value*x + float64(i+1)
+			}
+			return value
+		}
+
+		evaluate := func(surpluses []float64, x float64) float64 {
+			value := 0.0
+			for i := range surpluses {
+				value += surpluses[i] * basis.Compute(indices[i:i+1], []float64{x})
+			}
+			return value
+		}
+
+		surpluses := make([]float64, 0, len(indices))
+		for i := range indices {
+			surpluses = append(surpluses, target(nodes[i])-evaluate(surpluses, nodes[i]))
+		}
+
+		for _, x := range []float64{0.0, 0.01, 0.2, 0.33, 0.5, 0.72, 0.9, 0.99, 1.0} {
+			assert.Close(evaluate(surpluses, x), target(x), 1e-12, t)
+		}
+
+		levels, _ := internal.Decompose(indices)
+		for i := range indices {
+			if levels[i] > uint64(np) {
+				assert.Close(surpluses[i], 0.0, 1e-12, t)
+			}
+		}
+	}
+}
+
+func TestOpenIntegrateHigherPower(t *testing.T) {
+	const (
+		nd = 1
+		nl = 4
+		nn = 100000
+	)
+
+	grid := equidistant.NewOpen(nd)
+	indices := generateIndices(nd, 1<<nl-1, grid.Refine)
+
+	for np := uint(2); np <= 4; np++ {
+		basis := NewOpen(nd, np)
+		for i := range indices {
+			index := indices[i : i+1]
+			value := 0.0
+			for j := 0; j < nn; j++ {
+				value += basis.Compute(index, []float64{(float64(j) + 0.5) / nn})
+			}
+			assert.Close(basis.Integrate(index), value/nn, 1e-8, t)
+		}
+	}
+}
+
+func TestOpenIntegrateProduct(t *testing.T) {
+	const (
+		nd = 2
+		nn = 1000
+	)
+
+	indices := internal.Compose(
+		[]uint64{0, 0, 1, 2, 2, 0, 3, 3},
+		[]uint64{0, 0, 0, 2, 2, 0, 0, 6},
+	)
+
+	for np := uint(1); np <= 3; np++ {
+		basis := NewOpen(nd, np)
+		for _, pair := range [][2]uint{{0, 1}, {1, 2}, {2, 3}, {1, 3}, {3, 3}} {
+			one, two := indices[pair[0]*nd:(pair[0]+1)*nd], indices[pair[1]*nd:(pair[1]+1)*nd]
+			assert.Close(basis.IntegrateProduct(one, two),
+				integrateNumerically(basis, nd, nn, one, two), 1e-6, t)
+		}
+		one := indices[1*nd : 2*nd]
+		assert.Close(basis.IntegrateProduct(one), basis.Integrate(one), 1e-15, t)
+	}
+}
@@ -2,6 +2,7 @@ package polynomial
 
 import (
 	"math"
+	"sort"
 
 	"github.com/ready-steady/adapt/internal"
 )
@@ -17,6 +18,17 @@ func compute(index []uint64, point []float64, nd uint,
 	return value
 }
 
+func differentiate(index []uint64, point []float64, nd uint, orders []uint,
+	derive func(uint64, uint64, float64, uint) float64) float64 {
+
+	value := 1.0
+	for i := uint(0); i < nd && value != 0.0; i++ {
+		value *= derive(index[i]&internal.LEVEL_MASK,
+			index[i]>>internal.LEVEL_SIZE, point[i], orders[i])
+	}
+	return value
+}
+
 func equal(one, two float64) bool {
 	const ε = 1e-14 // ~= 2^(-46)
 	return one == two || math.Abs(one-two) < ε
@@ -32,3 +44,127 @@ func integrate(index []uint64, nd uint,
 	}
 	return value
 }
+
+func integrateProduct(indices [][]uint64, nd uint,
+	integrate func([]uint64, []uint64) float64) float64 {
+
+	nf := len(indices)
+	levels, orders := make([]uint64, nf), make([]uint64, nf)
+
+	value := 1.0
+	for i := uint(0); i < nd && value != 0.0; i++ {
+		for j := range indices {
+			levels[j] = indices[j][i] & internal.LEVEL_MASK
+			orders[j] = indices[j][i] >> internal.LEVEL_SIZE
+		}
+		value *= integrate(levels, orders)
+	}
+	return value
+}
+
+func support(index []uint64, nd uint,
+	support func(uint64, uint64) (float64, float64)) ([]float64, []float64) {
+
+	lower, upper := make([]float64, nd), make([]float64, nd)
+	for i := uint(0); i < nd; i++ {
+		lower[i], upper[i] = support(index[i]&internal.LEVEL_MASK,
+			index[i]>>internal.LEVEL_SIZE)
+	}
+	return lower, upper
+}
+
+// linear computes a derivative of a hat function centered at xi with the
+// half-width h at a point inside its support. At the node, the first
+// derivative is the average of the one-sided ones, which is zero, unless the
+// node lies on the boundary of the unit interval, in which case the one-sided
+// derivative from the inside is taken.
+func linear(x, xi, h float64, k uint) float64 {
+	switch k {
+	case 0:
+		return 1.0 - math.Abs(x-xi)/h
+	case 1:
+		switch {
+		case equal(x, xi) && xi <= 0.0:
+			return -1.0 / h
+		case equal(x, xi) && xi >= 1.0:
+			return 1.0 / h
+		case equal(x, xi):
+			return 0.0
+		case x < xi:
+			return 1.0 / h
+		default:
+			return -1.0 / h
+		}
+	}
+	return 0.0
+}
+
+// weigh computes the factor of the derivatives of a basis function supported
+// on [a, b] at a point. The factor is one inside the support and zero outside.
+// At an endpoint, where the derivatives are discontinuous, the factor is one
+// half, which gives the average of the one-sided derivatives, unless the
+// endpoint lies on the boundary of the unit interval, in which case the factor
+// is one, which gives the one-sided derivative from the inside.
+func weigh(x, a, b float64) float64 {
+	switch {
+	case equal(x, a):
+		if a <= 0.0 {
+			return 1.0
+		}
+		return 0.5
+	case equal(x, b):
+		if b >= 1.0 {
+			return 1.0
+		}
+		return 0.5
+	case x < a || x > b:
+		return 0.0
+	}
+	return 1.0
+}
+
+// product is a product of linear factors together with its first and second
+// derivatives.
+type product [3]float64
+
+func newProduct() product {
+	return product{1.0, 0.0, 0.0}
+}
+
+// multiply multiplies the product by (x - xj) / (xi - xj).
+func (self *product) multiply(x, xi, xj float64) {
+	a := 1.0 / (xi - xj)
+	f := (x - xj) * a
+	self[2] = self[2]*f + 2.0*self[1]*a
+	self[1] = self[1]*f + self[0]*a
+	self[0] *= f
+}
+
+// piecewise integrates a piecewise polynomial function of a given degree over
+// [a, b] by splitting the interval at a set of breakpoints.
+func piecewise(a, b float64, breakpoints []float64, degree uint,
+	target func(float64) float64) float64 {
+
+	if a >= b {
+		return 0.0
+	}
+
+	points := []float64{a}
+	sort.Float64s(breakpoints)
+	for _, x := range breakpoints {
+		if x > a && x < b && !equal(x, points[len(points)-1]) {
+			points = append(points, x)
+		}
+	}
+	points = append(points, b)
+
+	// Use a Gauss–Legendre quadrature rule to integrate. See the corresponding
+	// comment in Closed.integrate.
+	nodes := uint(math.Ceil((float64(degree) + 1.0) / 2.0))
+
+	value := 0.0
+	for i := 1; i < len(points); i++ {
+		value += quadrature(points[i-1], points[i], nodes, target)
+	}
+	return value
+}
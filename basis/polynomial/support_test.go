@@ -3,8 +3,11 @@ package polynomial
 import (
 	"math"
 	"math/rand"
+	"testing"
 
+	"github.com/ready-steady/adapt/basis"
 	"github.com/ready-steady/adapt/internal"
+	"github.com/ready-steady/assert"
 )
 
 func generateIndices(nd, ns uint, children func([]uint64) []uint64) []uint64 {
@@ -29,3 +32,135 @@ func generatePoints(nd, ns uint, indices []uint64,
 	}
 	return points
 }
+
+func integrateNumerically(basis interface {
+	Compute([]uint64, []float64) float64
+}, nd, nn uint, indices ...[]uint64) float64 {
+
+	point := make([]float64, nd)
+	value, count := 0.0, uint(1)
+	for i := uint(0); i < nd; i++ {
+		count *= nn
+	}
+	for i := uint(0); i < count; i++ {
+		for j, k := uint(0), i; j < nd; j, k = j+1, k/nn {
+			point[j] = (float64(k%nn) + 0.5) / float64(nn)
+		}
+		term := 1.0
+		for _, index := range indices {
+			term *= basis.Compute(index, point)
+		}
+		value += term
+	}
+	return value / float64(count)
+}
+
+func checkSupport(basis interface {
+	basis.Computer
+	basis.Supporter
+}, grid interface {
+	Compute([]uint64) []float64
+}, nd uint, indices []uint64, t *testing.T) {
+
+	const (
+		ns = 41
+	)
+
+	nn, count := uint(len(indices))/nd, uint(1)
+	for i := uint(0); i < nd; i++ {
+		count *= ns
+	}
+	point := make([]float64, nd)
+	for k := uint(0); k < nn; k++ {
+		index := indices[k*nd : (k+1)*nd]
+		lower, upper := basis.Support(index)
+		node := grid.Compute(index)
+		for i := uint(0); i < nd; i++ {
+			assert.Equal(0.0 <= lower[i] && lower[i] <= node[i], true, t)
+			assert.Equal(node[i] <= upper[i] && upper[i] <= 1.0, true, t)
+		}
+		for l := uint(0); l < count; l++ {
+			outside := false
+			for i, m := uint(0), l; i < nd; i, m = i+1, m/ns {
+				point[i] = float64(m%ns) / float64(ns-1)
+				outside = outside || point[i] < lower[i] || point[i] > upper[i]
+			}
+			if outside {
+				assert.Equal(basis.Compute(index, point), 0.0, t)
+			}
+		}
+	}
+}
+
+func checkDerivatives(basis interface {
+	basis.Computer
+	basis.Differentiator
+}, nd uint, indices []uint64, points []float64, t *testing.T) {
+
+	const (
+		h = 1e-6
+	)
+
+	nn, np := uint(len(indices))/nd, uint(len(points))/nd
+	shift := func(point []float64, i uint, Δ float64) []float64 {
+		point = append([]float64(nil), point...)
+		point[i] += Δ
+		return point
+	}
+	for k := uint(0); k < nn; k++ {
+		index := indices[k*nd : (k+1)*nd]
+		for l := uint(0); l < np; l++ {
+			point := points[l*nd : (l+1)*nd]
+			for i := uint(0); i < nd; i++ {
+				expected := (basis.Compute(index, shift(point, i, h)) -
+					basis.Compute(index, shift(point, i, -h))) / (2.0 * h)
+				assert.Close(basis.Differentiate(index, point, i), expected, 1e-6, t)
+				for j := uint(0); j < nd; j++ {
+					expected := (basis.Differentiate(index, shift(point, j, h), i) -
+						basis.Differentiate(index, shift(point, j, -h), i)) / (2.0 * h)
+					assert.Close(basis.DifferentiateTwice(index, point, i, j), expected, 1e-4, t)
+				}
+			}
+		}
+	}
+}
+
+func checkDerivativesAtNodes(basis interface {
+	basis.Computer
+	basis.Differentiator
+}, nd uint, indices []uint64, points []float64, t *testing.T) {
+
+	const (
+		h = 1e-6
+	)
+
+	nn, np := uint(len(indices))/nd, uint(len(points))/nd
+	shift := func(point []float64, i uint, Δ float64) []float64 {
+		point = append([]float64(nil), point...)
+		point[i] += Δ
+		return point
+	}
+	for k := uint(0); k < nn; k++ {
+		index := indices[k*nd : (k+1)*nd]
+		for l := uint(0); l < np; l++ {
+			point := points[l*nd : (l+1)*nd]
+			for i := uint(0); i < nd; i++ {
+				// Average the one-sided differences inside the unit interval.
+				left := (3.0*basis.Compute(index, point) -
+					4.0*basis.Compute(index, shift(point, i, -h)) +
+					basis.Compute(index, shift(point, i, -2.0*h))) / (2.0 * h)
+				right := (4.0*basis.Compute(index, shift(point, i, h)) -
+					3.0*basis.Compute(index, point) -
+					basis.Compute(index, shift(point, i, 2.0*h))) / (2.0 * h)
+				expected := (left + right) / 2.0
+				switch point[i] {
+				case 0.0:
+					expected = right
+				case 1.0:
+					expected = left
+				}
+				assert.Close(basis.Differentiate(index, point, i), expected, 1e-6, t)
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package chebyshev
+
+import (
+	"math"
+
+	"github.com/ready-steady/adapt/grid/equidistant"
+	"github.com/ready-steady/adapt/internal"
+)
+
+// Closed is a grid in [0, 1]^n.
+//
+// The grid is composed of nested Chebyshev–Gauss–Lobatto nodes, which are also
+// known as Clenshaw–Curtis nodes. The nodes are indexed in the same way as the
+// ones of equidistant.Closed; hence, the two grids share the same indexing,
+// parenting, and refinement.
+type Closed struct {
+	equidistant.Closed
+}
+
+// NewClosed creates a grid.
+func NewClosed(dimensions uint) *Closed {
+	return &Closed{*equidistant.NewClosed(dimensions)}
+}
+
+// Compute returns the nodes corresponding to a set of indices.
+func (self *Closed) Compute(indices []uint64) []float64 {
+	nodes := make([]float64, len(indices))
+	for i := range nodes {
+		level := indices[i] & internal.LEVEL_MASK
+		order := indices[i] >> internal.LEVEL_SIZE
+		nodes[i] = self.Node(level, order)
+	}
+	return nodes
+}
+
+// Node returns the node corresponding to an index in one dimension.
+func (_ *Closed) Node(level, order uint64) float64 {
+	if level == 0 {
+		return 0.5
+	}
+	return 0.5 * (1.0 - math.Cos(math.Pi*float64(order)/float64(uint64(1)<<level)))
+}
@@ -0,0 +1,81 @@
+package chebyshev
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/adapt/internal"
+	"github.com/ready-steady/assert"
+)
+
+func TestClosedCompute1D(t *testing.T) {
+	grid := NewClosed(1)
+
+	levels := []uint64{0, 1, 1, 2, 2, 3, 3, 3, 3}
+	orders := []uint64{0, 0, 2, 1, 3, 1, 3, 5, 7}
+	nodes := []float64{
+		0.5, 0.0, 1.0,
+		(1.0 - math.Cos(math.Pi/4.0)) / 2.0,
+		(1.0 - math.Cos(3.0*math.Pi/4.0)) / 2.0,
+		(1.0 - math.Cos(math.Pi/8.0)) / 2.0,
+		(1.0 - math.Cos(3.0*math.Pi/8.0)) / 2.0,
+		(1.0 - math.Cos(5.0*math.Pi/8.0)) / 2.0,
+		(1.0 - math.Cos(7.0*math.Pi/8.0)) / 2.0,
+	}
+
+	assert.Close(grid.Compute(internal.Compose(levels, orders)), nodes, 1e-15, t)
+}
+
+func TestClosedCompute2D(t *testing.T) {
+	grid := NewClosed(2)
+
+	levels := []uint64{
+		0, 0,
+		0, 1,
+		1, 0,
+		2, 1,
+	}
+
+	orders := []uint64{
+		0, 0,
+		0, 2,
+		0, 0,
+		3, 2,
+	}
+
+	nodes := []float64{
+		0.5, 0.5,
+		0.5, 1.0,
+		0.0, 0.5,
+		(1.0 - math.Cos(3.0*math.Pi/4.0)) / 2.0, 1.0,
+	}
+
+	assert.Close(grid.Compute(internal.Compose(levels, orders)), nodes, 1e-15, t)
+}
+
+func TestClosedNested(t *testing.T) {
+	const (
+		maxLevel = 6
+	)
+
+	grid := NewClosed(1)
+
+	for level := uint64(2); level <= maxLevel; level++ {
+		for order := uint64(0); order <= uint64(1)<<level; order += 2 {
+			assert.Close(grid.Node(level, order), grid.Node(level-1, order/2), 1e-15, t)
+		}
+	}
+}
+
+func TestClosedRefine(t *testing.T) {
+	grid := NewClosed(1)
+
+	levels := []uint64{0, 1, 1, 2, 2}
+	orders := []uint64{0, 0, 2, 1, 3}
+	childLevels := []uint64{1, 1, 2, 2, 3, 3, 3, 3}
+	childOrders := []uint64{0, 2, 1, 3, 1, 3, 5, 7}
+
+	indices := grid.Refine(internal.Compose(levels, orders))
+
+	assert.Equal(indices, internal.Compose(childLevels, childOrders), t)
+}
@@ -0,0 +1,2 @@
+// Package chebyshev provides Clenshaw–Curtis interpolation grids.
+package chebyshev
@@ -0,0 +1,83 @@
+// Package integration provides adaptive numerical integration.
+//
+// Integrals are computed using dimension-adaptive sparse grids of
+// Clenshaw–Curtis type, which are refined based on the contributions of the
+// grid nodes to the integral; see global.IntegralStrategy.
+package integration
+
+import (
+	"github.com/ready-steady/adapt/algorithm"
+	"github.com/ready-steady/adapt/algorithm/global"
+	"github.com/ready-steady/adapt/basis/lagrange"
+	"github.com/ready-steady/adapt/grid/chebyshev"
+)
+
+const (
+	// MinLevel is the default minimal level.
+	MinLevel = 1
+	// MaxLevel is the default maximal level.
+	MaxLevel = 10
+)
+
+// Integrator is an adaptive integrator.
+type Integrator struct {
+	ni uint
+	no uint
+
+	lmin uint
+	lmax uint
+	εa   float64
+	εr   float64
+
+	grid   *chebyshev.Closed
+	basis  *lagrange.Closed
+	domain *algorithm.Domain
+}
+
+// Integrate computes the integral of a function over the unit hypercube.
+func Integrate(target algorithm.Target, inputs, outputs uint,
+	absoluteError, relativeError float64) []float64 {
+
+	return New(inputs, outputs, absoluteError, relativeError).Integrate(target)
+}
+
+// New creates an integrator.
+func New(inputs, outputs uint, absoluteError, relativeError float64) *Integrator {
+	return &Integrator{
+		ni: inputs,
+		no: outputs,
+
+		lmin: MinLevel,
+		lmax: MaxLevel,
+		εa:   absoluteError,
+		εr:   relativeError,
+
+		grid:  chebyshev.NewClosed(inputs),
+		basis: lagrange.NewClosed(inputs),
+	}
+}
+
+// Limit sets the minimal and maximal levels.
+func (self *Integrator) Limit(minLevel, maxLevel uint) {
+	self.lmin, self.lmax = minLevel, maxLevel
+}
+
+// Restrict sets the domain of integration, which is the unit hypercube by
+// default.
+func (self *Integrator) Restrict(domain *algorithm.Domain) {
+	self.domain = domain
+}
+
+// Integrate computes the integral of a function.
+func (self *Integrator) Integrate(target algorithm.Target) []float64 {
+	return self.Compute(target).Integral
+}
+
+// Compute constructs the interpolant underlying the integral of a function.
+func (self *Integrator) Compute(target algorithm.Target) *algorithm.Surrogate {
+	algorithm := global.New(self.ni, self.no, self.grid, self.basis)
+	algorithm.Restrict(self.domain)
+	strategy := global.NewIntegralStrategy(self.ni, self.no, self.grid,
+		self.lmin, self.lmax, self.εa, self.εr)
+	return algorithm.Compute(target, strategy)
+}
@@ -0,0 +1,29 @@
+package integration
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/adapt/algorithm"
+	"github.com/ready-steady/assert"
+)
+
+func TestIntegrate(t *testing.T) {
+	integral := Integrate(func(x, y []float64) {
+		y[0] = math.Exp(x[0] + x[1])
+		y[1] = x[0] * x[0] * x[1]
+	}, 2, 2, 1e-10, 1e-10)
+	assert.Close(integral, []float64{(math.E - 1.0) * (math.E - 1.0), 1.0 / 6.0}, 1e-10, t)
+}
+
+func TestIntegrateDomain(t *testing.T) {
+	integrator := New(3, 1, 1e-8, 1e-8)
+	integrator.Restrict(algorithm.NewDomain([]float64{0.0, -1.0, 0.0},
+		[]float64{math.Pi, 1.0, 2.0}))
+	surrogate := integrator.Compute(func(x, y []float64) {
+		y[0] = (1.0 + math.Sin(x[0])) * (1.0 + x[1]*x[1]) * math.Exp(x[2]/2.0)
+	})
+	assert.Close(surrogate.Integral, []float64{(math.Pi + 2.0) * 8.0 / 3.0 *
+		2.0 * (math.E - 1.0)}, 1e-8, t)
+	assert.Equal(surrogate.Nodes < 1000, true, t)
+}
@@ -0,0 +1,72 @@
+package internal
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ready-steady/adapt/basis"
+)
+
+// Overlap finds the pairs of basis functions whose supports intersect in a set
+// of positive measure. For each basis function, the result contains the
+// positions of the basis functions overlapping with it, including itself, that
+// are larger than or equal to its own position, in increasing order.
+//
+// The supports are swept along the first dimension, so the cost is
+// proportional to the number of the pairs that overlap in that dimension.
+func Overlap(supporter basis.Supporter, indices []uint64, nd uint) [][]uint {
+	nn := uint(len(indices)) / nd
+
+	lower, upper := make([]float64, nn*nd), make([]float64, nn*nd)
+	for i := uint(0); i < nn; i++ {
+		a, b := supporter.Support(indices[i*nd : (i+1)*nd])
+		copy(lower[i*nd:], a)
+		copy(upper[i*nd:], b)
+	}
+
+	order := make([]uint, nn)
+	for i := range order {
+		order[i] = uint(i)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return lower[order[i]*nd] < lower[order[j]*nd]
+	})
+
+	overlap := func(i, j uint) bool {
+		for k := uint(0); k < nd; k++ {
+			a := math.Max(lower[i*nd+k], lower[j*nd+k])
+			b := math.Min(upper[i*nd+k], upper[j*nd+k])
+			if a >= b {
+				return false
+			}
+		}
+		return true
+	}
+
+	result := make([][]uint, nn)
+	for k := uint(0); k < nn; k++ {
+		i := order[k]
+		result[i] = append(result[i], i)
+		for l := k + 1; l < nn; l++ {
+			j := order[l]
+			if lower[j*nd] >= upper[i*nd] {
+				break
+			}
+			if !overlap(i, j) {
+				continue
+			}
+			if i < j {
+				result[i] = append(result[i], j)
+			} else {
+				result[j] = append(result[j], i)
+			}
+		}
+	}
+	for i := range result {
+		sort.Slice(result[i], func(j, k int) bool {
+			return result[i][j] < result[i][k]
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,204 @@
+package probability
+
+import (
+	"math"
+)
+
+// Distribution is a univariate probability distribution.
+type Distribution interface {
+	// Cumulate evaluates the cumulative distribution function.
+	Cumulate(float64) float64
+
+	// Invert evaluates the inverse of the cumulative distribution function.
+	Invert(float64) float64
+}
+
+// Uniform is a uniform distribution.
+type Uniform struct {
+	a float64
+	b float64
+}
+
+// NewUniform returns a uniform distribution on [a, b].
+func NewUniform(a, b float64) *Uniform {
+	return &Uniform{a, b}
+}
+
+// Cumulate evaluates the cumulative distribution function.
+func (self *Uniform) Cumulate(x float64) float64 {
+	return math.Min(1.0, math.Max(0.0, (x-self.a)/(self.b-self.a)))
+}
+
+// Invert evaluates the inverse of the cumulative distribution function.
+func (self *Uniform) Invert(p float64) float64 {
+	return self.a + (self.b-self.a)*p
+}
+
+// Gaussian is a Gaussian distribution.
+type Gaussian struct {
+	μ float64
+	σ float64
+}
+
+// NewGaussian returns a Gaussian distribution with mean μ and standard
+// deviation σ.
+func NewGaussian(μ, σ float64) *Gaussian {
+	return &Gaussian{μ, σ}
+}
+
+// Cumulate evaluates the cumulative distribution function.
+func (self *Gaussian) Cumulate(x float64) float64 {
+	return 0.5 * math.Erfc(-(x-self.μ)/(self.σ*math.Sqrt2))
+}
+
+// Invert evaluates the inverse of the cumulative distribution function, which
+// is infinite at zero and one.
+func (self *Gaussian) Invert(p float64) float64 {
+	return self.μ + self.σ*math.Sqrt2*math.Erfinv(2.0*p-1.0)
+}
+
+// LogNormal is a log-normal distribution.
+type LogNormal struct {
+	Gaussian
+}
+
+// NewLogNormal returns a log-normal distribution whose logarithm has mean μ and
+// standard deviation σ.
+func NewLogNormal(μ, σ float64) *LogNormal {
+	return &LogNormal{Gaussian{μ, σ}}
+}
+
+// Cumulate evaluates the cumulative distribution function.
+func (self *LogNormal) Cumulate(x float64) float64 {
+	if x <= 0.0 {
+		return 0.0
+	}
+	return self.Gaussian.Cumulate(math.Log(x))
+}
+
+// Invert evaluates the inverse of the cumulative distribution function, which
+// is zero at zero and infinite at one.
+func (self *LogNormal) Invert(p float64) float64 {
+	return math.Exp(self.Gaussian.Invert(p))
+}
+
+// Beta is a beta distribution.
+type Beta struct {
+	α float64
+	β float64
+	a float64
+	b float64
+
+	lnB float64
+}
+
+// NewBeta returns a beta distribution with shape parameters α and β on [a, b].
+func NewBeta(α, β, a, b float64) *Beta {
+	lα, _ := math.Lgamma(α)
+	lβ, _ := math.Lgamma(β)
+	lαβ, _ := math.Lgamma(α + β)
+	return &Beta{α, β, a, b, lα + lβ - lαβ}
+}
+
+// Cumulate evaluates the cumulative distribution function using a continued
+// fraction.
+func (self *Beta) Cumulate(x float64) float64 {
+	return incompleteBeta((x-self.a)/(self.b-self.a), self.α, self.β, self.lnB)
+}
+
+// Invert evaluates the inverse of the cumulative distribution function using a
+// safeguarded Newton's method.
+func (self *Beta) Invert(p float64) float64 {
+	const (
+		ε = 1e-15
+		n = 100
+	)
+
+	if p <= 0.0 {
+		return self.a
+	}
+	if p >= 1.0 {
+		return self.b
+	}
+
+	// Combine the bisection method with Newton's method in order to ensure
+	// convergence.
+	lower, upper, x := 0.0, 1.0, 0.5
+	for i := 0; i < n; i++ {
+		Δ := incompleteBeta(x, self.α, self.β, self.lnB) - p
+		if Δ < 0.0 {
+			lower = x
+		} else {
+			upper = x
+		}
+		density := math.Exp((self.α-1.0)*math.Log(x) + (self.β-1.0)*math.Log(1.0-x) - self.lnB)
+		y := x - Δ/density
+		if !(y > lower && y < upper) {
+			y = (lower + upper) / 2.0
+		}
+		if math.Abs(y-x) < ε {
+			x = y
+			break
+		}
+		x = y
+	}
+
+	return self.a + (self.b-self.a)*x
+}
+
+// incompleteBeta evaluates the regularized incomplete beta function using a
+// continued fraction.
+func incompleteBeta(x, α, β, lnB float64) float64 {
+	if x <= 0.0 {
+		return 0.0
+	}
+	if x >= 1.0 {
+		return 1.0
+	}
+	front := math.Exp(α*math.Log(x) + β*math.Log(1.0-x) - lnB)
+	if x < (α+1.0)/(α+β+2.0) {
+		return front * continuedFraction(x, α, β) / α
+	} else {
+		return 1.0 - front*continuedFraction(1.0-x, β, α)/β
+	}
+}
+
+func continuedFraction(x, α, β float64) float64 {
+	const (
+		ε = 1e-16
+		δ = 1e-300
+		n = 1000
+	)
+
+	c, d := 1.0, 1.0-(α+β)*x/(α+1.0)
+	if math.Abs(d) < δ {
+		d = δ
+	}
+	d = 1.0 / d
+	value := d
+	for i := 1; i <= n; i++ {
+		m := float64(i)
+		for j := 0; j < 2; j++ {
+			var a float64
+			if j == 0 {
+				a = m * (β - m) * x / ((α + 2.0*m - 1.0) * (α + 2.0*m))
+			} else {
+				a = -(α + m) * (α + β + m) * x / ((α + 2.0*m) * (α + 2.0*m + 1.0))
+			}
+			d = 1.0 + a*d
+			if math.Abs(d) < δ {
+				d = δ
+			}
+			c = 1.0 + a/c
+			if math.Abs(c) < δ {
+				c = δ
+			}
+			d = 1.0 / d
+			value *= d * c
+			if j == 1 && math.Abs(d*c-1.0) < ε {
+				return value
+			}
+		}
+	}
+	return value
+}
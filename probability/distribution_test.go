@@ -0,0 +1,43 @@
+package probability
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/assert"
+)
+
+func TestBeta(t *testing.T) {
+	distribution := NewBeta(2.0, 3.0, -1.0, 3.0)
+
+	points := []float64{-1.0, 0.0, 1.0, 2.0, 3.0}
+	values := []float64{0.0, 0.26171875, 0.6875, 0.94921875, 1.0}
+
+	for i := range points {
+		assert.Close(distribution.Cumulate(points[i]), values[i], 1e-14, t)
+		assert.Close(distribution.Invert(values[i]), points[i], 1e-12, t)
+	}
+}
+
+func TestGaussian(t *testing.T) {
+	distribution := NewGaussian(1.0, 2.0)
+
+	assert.Close(distribution.Cumulate(1.0), 0.5, 1e-15, t)
+	assert.Close(distribution.Cumulate(3.0), 0.8413447460685429, 1e-15, t)
+	assert.Close(distribution.Invert(0.8413447460685429), 3.0, 1e-12, t)
+}
+
+func TestLogNormal(t *testing.T) {
+	distribution := NewLogNormal(0.0, 1.0)
+
+	assert.Close(distribution.Cumulate(1.0), 0.5, 1e-15, t)
+	assert.Close(distribution.Invert(0.5), 1.0, 1e-15, t)
+	assert.Close(distribution.Invert(distribution.Cumulate(math.E)), math.E, 1e-12, t)
+}
+
+func TestUniform(t *testing.T) {
+	distribution := NewUniform(-1.0, 3.0)
+
+	assert.Equal(distribution.Cumulate(0.0), 0.25, t)
+	assert.Equal(distribution.Invert(0.25), 0.0, t)
+}
@@ -0,0 +1,9 @@
+// Package probability provides probability distributions and transformations
+// for propagating uncertainty through interpolants.
+//
+// An interpolant is constructed on the unit hypercube, which is equipped with
+// the uniform distribution. A transformation maps the unit hypercube onto the
+// support of a joint distribution so that the integral of an interpolant of
+// the transformed target is the expectation with respect to the joint
+// distribution.
+package probability
@@ -0,0 +1,169 @@
+package probability
+
+import (
+	"math"
+
+	"github.com/ready-steady/adapt/algorithm"
+)
+
+// Transform is a transformation from the uniform distribution on the unit
+// hypercube to a joint distribution.
+//
+// The joint distribution is given by its marginal distributions and,
+// optionally, by the correlation matrix of a Gaussian copula. If a marginal
+// distribution has an unbounded support, such as Gaussian and LogNormal, or if
+// the inputs are correlated, the transformation is infinite at the boundary of
+// the unit hypercube, and it should then be used with grids that do not include
+// the boundary. Otherwise, any grid can be used.
+type Transform struct {
+	ni uint
+
+	marginals []Distribution
+	factor    []float64
+}
+
+// Evaluator computes the values of an interpolant at a set of points.
+type Evaluator interface {
+	Evaluate(*algorithm.Surrogate, []float64) []float64
+}
+
+// NewTransform creates a transformation. The correlation matrix is a symmetric
+// positive-definite n-by-n matrix with ones on the diagonal stored in row-major
+// order; if it is nil, the inputs are independent.
+func NewTransform(marginals []Distribution, correlation []float64) *Transform {
+	ni := uint(len(marginals))
+	transform := &Transform{
+		ni:        ni,
+		marginals: marginals,
+	}
+	if correlation != nil {
+		if uint(len(correlation)) != ni*ni {
+			panic("the correlation matrix has a wrong size")
+		}
+		for i := uint(0); i < ni; i++ {
+			if correlation[i*ni+i] != 1.0 {
+				panic("the correlation matrix should have ones on the diagonal")
+			}
+			for j := uint(0); j < i; j++ {
+				if correlation[i*ni+j] != correlation[j*ni+i] {
+					panic("the correlation matrix should be symmetric")
+				}
+			}
+		}
+		transform.factor = decompose(correlation, ni)
+	}
+	return transform
+}
+
+// Forward maps points from the unit hypercube to the joint distribution.
+func (self *Transform) Forward(points []float64) []float64 {
+	ni := self.ni
+	np := uint(len(points)) / ni
+	result := make([]float64, np*ni)
+	for i := uint(0); i < np; i++ {
+		copy(result[i*ni:(i+1)*ni], points[i*ni:(i+1)*ni])
+		self.forward(result[i*ni : (i+1)*ni])
+	}
+	return result
+}
+
+// Backward maps points from the joint distribution to the unit hypercube.
+func (self *Transform) Backward(points []float64) []float64 {
+	ni := self.ni
+	np := uint(len(points)) / ni
+	result := make([]float64, np*ni)
+	for i := uint(0); i < np; i++ {
+		copy(result[i*ni:(i+1)*ni], points[i*ni:(i+1)*ni])
+		self.backward(result[i*ni : (i+1)*ni])
+	}
+	return result
+}
+
+// Target wraps a function defined with respect to the joint distribution so
+// that it can be interpolated on the unit hypercube.
+func (self *Transform) Target(target algorithm.Target) algorithm.Target {
+	return func(x, y []float64) {
+		x = append([]float64(nil), x...)
+		self.forward(x)
+		target(x, y)
+	}
+}
+
+// Evaluate computes the values of an interpolant of a wrapped target at a set
+// of points given with respect to the joint distribution.
+func (self *Transform) Evaluate(evaluator Evaluator, surrogate *algorithm.Surrogate,
+	points []float64) []float64 {
+
+	return evaluator.Evaluate(surrogate, self.Backward(points))
+}
+
+func (self *Transform) forward(point []float64) {
+	ni := self.ni
+	if self.factor != nil {
+		z := make([]float64, ni)
+		for i := uint(0); i < ni; i++ {
+			z[i] = normalInvert(point[i])
+		}
+		for i := uint(0); i < ni; i++ {
+			sum := 0.0
+			for j := uint(0); j <= i; j++ {
+				sum += self.factor[i*ni+j] * z[j]
+			}
+			point[i] = normalCumulate(sum)
+		}
+	}
+	for i := uint(0); i < ni; i++ {
+		point[i] = self.marginals[i].Invert(point[i])
+	}
+}
+
+func (self *Transform) backward(point []float64) {
+	ni := self.ni
+	for i := uint(0); i < ni; i++ {
+		point[i] = self.marginals[i].Cumulate(point[i])
+	}
+	if self.factor != nil {
+		z := make([]float64, ni)
+		for i := uint(0); i < ni; i++ {
+			sum := normalInvert(point[i])
+			for j := uint(0); j < i; j++ {
+				sum -= self.factor[i*ni+j] * z[j]
+			}
+			z[i] = sum / self.factor[i*ni+i]
+		}
+		for i := uint(0); i < ni; i++ {
+			point[i] = normalCumulate(z[i])
+		}
+	}
+}
+
+// decompose computes the lower Cholesky factor of a symmetric positive-definite
+// matrix.
+func decompose(A []float64, n uint) []float64 {
+	L := make([]float64, n*n)
+	for i := uint(0); i < n; i++ {
+		for j := uint(0); j <= i; j++ {
+			sum := A[i*n+j]
+			for k := uint(0); k < j; k++ {
+				sum -= L[i*n+k] * L[j*n+k]
+			}
+			if i == j {
+				if sum <= 0.0 {
+					panic("the correlation matrix should be positive definite")
+				}
+				L[i*n+i] = math.Sqrt(sum)
+			} else {
+				L[i*n+j] = sum / L[j*n+j]
+			}
+		}
+	}
+	return L
+}
+
+func normalCumulate(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+func normalInvert(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2.0*p-1.0)
+}
@@ -0,0 +1,110 @@
+package probability
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/adapt/algorithm/global"
+	"github.com/ready-steady/adapt/algorithm/local"
+	"github.com/ready-steady/adapt/basis/lagrange"
+	"github.com/ready-steady/adapt/basis/polynomial"
+	"github.com/ready-steady/adapt/grid/chebyshev"
+	"github.com/ready-steady/adapt/grid/equidistant"
+	"github.com/ready-steady/assert"
+)
+
+func TestTransformCorrelation(t *testing.T) {
+	transform := NewTransform([]Distribution{
+		NewGaussian(0.0, 1.0),
+		NewBeta(2.0, 3.0, 0.0, 1.0),
+		NewLogNormal(0.0, 0.5),
+	}, []float64{
+		1.0, 0.5, 0.2,
+		0.5, 1.0, 0.3,
+		0.2, 0.3, 1.0,
+	})
+
+	points := []float64{
+		0.5, 0.5, 0.5,
+		0.1, 0.2, 0.3,
+		0.9, 0.7, 0.4,
+	}
+
+	assert.Close(transform.Backward(transform.Forward(points)), points, 1e-12, t)
+}
+
+func TestTransformInvalid(t *testing.T) {
+	marginals := []Distribution{NewGaussian(0.0, 1.0), NewGaussian(0.0, 1.0)}
+	for _, correlation := range [][]float64{
+		{1.0, 0.5, 0.5},
+		{2.0, 0.5, 0.5, 2.0},
+		{1.0, 0.5, 0.2, 1.0},
+		{1.0, 2.0, 2.0, 1.0},
+	} {
+		func() {
+			defer func() {
+				assert.Equal(recover() != nil, true, t)
+			}()
+			NewTransform(marginals, correlation)
+		}()
+	}
+}
+
+func TestTransformExpectation(t *testing.T) {
+	const (
+		ni = 2
+		no = 1
+	)
+
+	transform := NewTransform([]Distribution{
+		NewUniform(2.0, 4.0),
+		NewBeta(2.0, 3.0, 0.0, 1.0),
+	}, nil)
+
+	// The marginals have bounded supports, and the inputs are independent;
+	// hence, the boundary can be included.
+	grid, basis := chebyshev.NewClosed(ni), lagrange.NewClosed(ni)
+	algorithm := global.New(ni, no, grid, basis)
+	strategy := global.NewStrategy(ni, no, grid, 1, 10, 1e-12, 1e-12)
+
+	target := func(x, y []float64) {
+		y[0] = x[0] * x[1]
+	}
+
+	surrogate := algorithm.Compute(transform.Target(target), strategy)
+
+	// E[X1 X2] = E[X1] E[X2] = 3 × 2/5.
+	assert.Close(surrogate.Integral, []float64{1.2}, 1e-6, t)
+
+	points := []float64{2.5, 0.2, 3.5, 0.8}
+	values := transform.Evaluate(algorithm, surrogate, points)
+	assert.Close(values, []float64{0.5, 2.8}, 1e-4, t)
+}
+
+func TestTransformUnbounded(t *testing.T) {
+	const (
+		ni = 2
+		no = 1
+	)
+
+	transform := NewTransform([]Distribution{
+		NewGaussian(1.0, 0.5),
+		NewLogNormal(0.0, 0.5),
+	}, []float64{
+		1.0, 0.5,
+		0.5, 1.0,
+	})
+
+	grid, basis := equidistant.NewOpen(ni), polynomial.NewOpen(ni, 1)
+	algorithm := local.New(ni, no, grid, basis)
+	strategy := local.NewStrategy(ni, no, grid, 1, 10, 1e-3)
+
+	target := func(x, y []float64) {
+		y[0] = x[0] + x[1]
+	}
+
+	surrogate := algorithm.Compute(transform.Target(target), strategy)
+
+	// E[X1 + X2] = 1 + exp(0.5^2 / 2).
+	assert.Close(surrogate.Integral, []float64{1.0 + math.Exp(0.125)}, 1e-2, t)
+}
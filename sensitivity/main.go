@@ -0,0 +1,291 @@
+// Package sensitivity provides global sensitivity analysis of interpolants.
+//
+// The Sobol indices are computed analytically from the hierarchical surpluses
+// of an interpolant. Since an interpolant is a sum of tensor products of
+// one-dimensional basis functions, the conditional expectations needed for the
+// variance decomposition are sums of products of one-dimensional integrals.
+// The basis functions of level zero are assumed to be equal to one, which is
+// the case for the bases in polynomial and lagrange.
+package sensitivity
+
+import (
+	"encoding/binary"
+
+	"github.com/ready-steady/adapt/algorithm"
+	"github.com/ready-steady/adapt/basis"
+	"github.com/ready-steady/adapt/internal"
+)
+
+// Basis is an interpolation basis. The supports of the basis functions
+// determine which products of them need to be integrated.
+type Basis interface {
+	basis.IntegratorProduct
+	basis.Supporter
+}
+
+// Indices contains Sobol sensitivity indices.
+type Indices struct {
+	First       []float64 // First-order indices (inputs × outputs)
+	Total       []float64 // Total-effect indices (inputs × outputs)
+	Interaction []float64 // Second-order indices (inputs × inputs × outputs)
+	Variance    []float64 // Total variance (outputs)
+}
+
+// Compute computes the Sobol indices of an interpolant. The indices of an
+// output whose variance is zero are set to zero.
+func Compute(surrogate *algorithm.Surrogate, basis Basis) *Indices {
+	ni, no := surrogate.Inputs, surrogate.Outputs
+
+	decomposition := newDecomposition(surrogate, basis)
+
+	all := make([]bool, ni)
+	for i := range all {
+		all[i] = true
+	}
+
+	μ2 := decomposition.compute(make([]bool, ni))
+	variance := subtract(decomposition.compute(all), μ2)
+
+	first := make([]float64, ni*no)
+	total := make([]float64, ni*no)
+	interaction := make([]float64, ni*ni*no)
+
+	partial := make([][]float64, ni)
+	for i := uint(0); i < ni; i++ {
+		subset := make([]bool, ni)
+		subset[i] = true
+		partial[i] = subtract(decomposition.compute(subset), μ2)
+
+		all[i] = false
+		complement := subtract(decomposition.compute(all), μ2)
+		all[i] = true
+
+		for k := uint(0); k < no; k++ {
+			if variance[k] <= 0.0 {
+				continue
+			}
+			first[i*no+k] = partial[i][k] / variance[k]
+			total[i*no+k] = (variance[k] - complement[k]) / variance[k]
+		}
+	}
+
+	for i := uint(0); i < ni; i++ {
+		for j := i + 1; j < ni; j++ {
+			subset := make([]bool, ni)
+			subset[i], subset[j] = true, true
+			closed := subtract(decomposition.compute(subset), μ2)
+			for k := uint(0); k < no; k++ {
+				if variance[k] <= 0.0 {
+					continue
+				}
+				value := (closed[k] - partial[i][k] - partial[j][k]) / variance[k]
+				interaction[(i*ni+j)*no+k] = value
+				interaction[(j*ni+i)*no+k] = value
+			}
+		}
+	}
+
+	return &Indices{
+		First:       first,
+		Total:       total,
+		Interaction: interaction,
+		Variance:    variance,
+	}
+}
+
+// decomposition is a means of computing the second moments of conditional
+// expectations of an interpolant.
+type decomposition struct {
+	ni uint
+	no uint
+	nn uint
+
+	basis     Basis
+	surpluses []float64
+
+	indices   [][]uint64         // One-dimensional indices
+	positions []uint             // Positions of the one-dimensional indices
+	means     [][]float64        // Integrals of the one-dimensional basis functions
+	products  []map[uint]float64 // Integrals of their pairwise products
+}
+
+func newDecomposition(surrogate *algorithm.Surrogate, basis Basis) *decomposition {
+	ni, no, nn := surrogate.Inputs, surrogate.Outputs, surrogate.Nodes
+
+	indices := make([][]uint64, ni)
+	positions := make([]uint, nn*ni)
+	means := make([][]float64, ni)
+	products := make([]map[uint]float64, ni)
+
+	one := make([]uint64, ni)
+	for j := uint(0); j < ni; j++ {
+		unique := make(map[uint64]uint)
+		for i := uint(0); i < nn; i++ {
+			index := surrogate.Indices[i*ni+j]
+			k, ok := unique[index]
+			if !ok {
+				k = uint(len(indices[j]))
+				unique[index] = k
+				indices[j] = append(indices[j], index)
+			}
+			positions[i*ni+j] = k
+		}
+
+		nu := uint(len(indices[j]))
+		means[j] = make([]float64, nu)
+		for k := uint(0); k < nu; k++ {
+			one[j] = indices[j][k]
+			means[j][k] = basis.IntegrateProduct(one)
+		}
+		one[j] = 0
+		products[j] = make(map[uint]float64)
+	}
+
+	return &decomposition{
+		ni: ni,
+		no: no,
+		nn: nn,
+
+		basis:     basis,
+		surpluses: surrogate.Surpluses,
+
+		indices:   indices,
+		positions: positions,
+		means:     means,
+		products:  products,
+	}
+}
+
+// compute returns the second moment of the conditional expectation of the
+// interpolant given the inputs of a subset. For the empty subset, the result
+// is the square of the expectation.
+func (self *decomposition) compute(subset []bool) []float64 {
+	ni, no, nn := self.ni, self.no, self.nn
+
+	// Group the basis functions by their indices in the dimensions of the
+	// subset; the rest of the dimensions are integrated out.
+	mapping := make(map[string]uint)
+	key := make([]byte, ni*binary.MaxVarintLen64)
+	groups := []uint{}
+	weights := []float64{}
+	for i := uint(0); i < nn; i++ {
+		factor, size := 1.0, 0
+		for j := uint(0); j < ni; j++ {
+			position := self.positions[i*ni+j]
+			if subset[j] {
+				size += binary.PutUvarint(key[size:], uint64(position))
+			} else {
+				factor *= self.means[j][position]
+			}
+		}
+		if factor == 0.0 {
+			continue
+		}
+		k, ok := mapping[string(key[:size])]
+		if !ok {
+			k = uint(len(groups))
+			mapping[string(key[:size])] = k
+			groups = append(groups, i)
+			weights = append(weights, make([]float64, no)...)
+		}
+		for l := uint(0); l < no; l++ {
+			weights[k*no+l] += factor * self.surpluses[i*no+l]
+		}
+	}
+
+	// Only the pairs of groups whose supports overlap in the dimensions of the
+	// subset contribute.
+	dimensions := []uint{}
+	for j := uint(0); j < ni; j++ {
+		if subset[j] {
+			dimensions = append(dimensions, j)
+		}
+	}
+	ng, ns := uint(len(groups)), uint(len(dimensions))
+	neighbors := [][]uint{}
+	if ns == 0 {
+		neighbors = make([][]uint, ng)
+		for k := range neighbors {
+			neighbors[k] = []uint{uint(k)}
+		}
+	} else {
+		keys := make([]uint64, 0, ng*ns)
+		for _, i := range groups {
+			for _, j := range dimensions {
+				keys = append(keys, self.indices[j][self.positions[i*ni+j]])
+			}
+		}
+		neighbors = internal.Overlap(&projection{self.basis, dimensions, ni}, keys, ns)
+	}
+
+	moment := make([]float64, no)
+	for k := uint(0); k < ng; k++ {
+		for _, l := range neighbors[k] {
+			product := 1.0
+			if k != l {
+				product = 2.0
+			}
+			for _, j := range dimensions {
+				product *= self.product(j, self.positions[groups[k]*ni+j],
+					self.positions[groups[l]*ni+j])
+				if product == 0.0 {
+					break
+				}
+			}
+			if product == 0.0 {
+				continue
+			}
+			for m := uint(0); m < no; m++ {
+				moment[m] += product * weights[k*no+m] * weights[l*no+m]
+			}
+		}
+	}
+
+	return moment
+}
+
+// product returns the integral of the product of two one-dimensional basis
+// functions of a dimension, which is computed once.
+func (self *decomposition) product(j, k, l uint) float64 {
+	if k > l {
+		k, l = l, k
+	}
+	key := k*uint(len(self.indices[j])) + l
+	if value, ok := self.products[j][key]; ok {
+		return value
+	}
+	one, two := make([]uint64, self.ni), make([]uint64, self.ni)
+	one[j], two[j] = self.indices[j][k], self.indices[j][l]
+	value := self.basis.IntegrateProduct(one, two)
+	self.products[j][key] = value
+	return value
+}
+
+// projection is a basis.Supporter of the basis functions restricted to a
+// subset of the dimensions, whose levels are zero in the other dimensions.
+type projection struct {
+	supporter  basis.Supporter
+	dimensions []uint
+	ni         uint
+}
+
+func (self *projection) Support(index []uint64) ([]float64, []float64) {
+	full := make([]uint64, self.ni)
+	for k, j := range self.dimensions {
+		full[j] = index[k]
+	}
+	lower, upper := self.supporter.Support(full)
+	a, b := make([]float64, len(self.dimensions)), make([]float64, len(self.dimensions))
+	for k, j := range self.dimensions {
+		a[k], b[k] = lower[j], upper[j]
+	}
+	return a, b
+}
+
+func subtract(minuend, subtrahend []float64) []float64 {
+	result := make([]float64, len(minuend))
+	for i := range minuend {
+		result[i] = minuend[i] - subtrahend[i]
+	}
+	return result
+}
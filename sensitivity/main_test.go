@@ -0,0 +1,71 @@
+package sensitivity
+
+import (
+	"testing"
+
+	"github.com/ready-steady/adapt/algorithm/local"
+	"github.com/ready-steady/adapt/basis/polynomial"
+	"github.com/ready-steady/adapt/grid/equidistant"
+	"github.com/ready-steady/assert"
+)
+
+func TestCompute(t *testing.T) {
+	const (
+		ni = 2
+		no = 2
+	)
+
+	target := func(x, y []float64) {
+		y[0] = x[0] + 2.0*x[1] + x[0]*x[1]
+		y[1] = x[1]
+	}
+
+	first := []float64{27.0 / 103.0, 0.0, 75.0 / 103.0, 1.0}
+	total := []float64{28.0 / 103.0, 0.0, 76.0 / 103.0, 1.0}
+	interaction := []float64{0.0, 0.0, 1.0 / 103.0, 0.0, 1.0 / 103.0, 0.0, 0.0, 0.0}
+	variance := []float64{103.0 / 144.0, 1.0 / 12.0}
+
+	test := func(grid local.Grid, guide local.Guide, basis interface {
+		local.Basis
+		Basis
+	}) {
+
+		algorithm := local.New(ni, no, grid, basis)
+		strategy := local.NewStrategy(ni, no, guide, 1, 10, 1e-4)
+		surrogate := algorithm.Compute(target, strategy)
+
+		indices := Compute(surrogate, basis)
+
+		assert.Close(indices.First, first, 1e-14, t)
+		assert.Close(indices.Total, total, 1e-14, t)
+		assert.Close(indices.Interaction, interaction, 1e-14, t)
+		assert.Close(indices.Variance, variance, 1e-14, t)
+	}
+
+	closed := equidistant.NewClosed(ni)
+	test(closed, closed, polynomial.NewClosed(ni, 1))
+
+	open := equidistant.NewOpen(ni)
+	test(open, open, polynomial.NewOpen(ni, 1))
+}
+
+func TestComputeConstant(t *testing.T) {
+	const (
+		ni = 2
+		no = 1
+	)
+
+	grid, basis := equidistant.NewClosed(ni), polynomial.NewClosed(ni, 1)
+	algorithm := local.New(ni, no, grid, basis)
+	strategy := local.NewStrategy(ni, no, grid, 1, 10, 1e-4)
+	surrogate := algorithm.Compute(func(x, y []float64) {
+		y[0] = 42.0
+	}, strategy)
+
+	indices := Compute(surrogate, basis)
+
+	assert.Equal(indices.Variance, []float64{0.0}, t)
+	assert.Equal(indices.First, []float64{0.0, 0.0}, t)
+	assert.Equal(indices.Total, []float64{0.0, 0.0}, t)
+	assert.Equal(indices.Interaction, []float64{0.0, 0.0, 0.0, 0.0}, t)
+}
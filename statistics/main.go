@@ -0,0 +1,77 @@
+// Package statistics provides statistical summaries of interpolants.
+//
+// The summaries are computed exactly from the hierarchical surpluses of an
+// interpolant using the integrals of products of basis functions. They are
+// taken with respect to the uniform distribution on the domain of the
+// interpolant; see also the probability package.
+package statistics
+
+import (
+	"math"
+
+	"github.com/ready-steady/adapt/algorithm"
+	"github.com/ready-steady/adapt/basis"
+)
+
+// Basis is an interpolation basis. The supports of the basis functions
+// determine which products of them need to be integrated.
+type Basis interface {
+	basis.IntegratorProduct
+	basis.Supporter
+}
+
+// Moments contains the moments of an interpolant.
+type Moments struct {
+	Mean     []float64 // Expectation
+	Variance []float64 // Variance
+	Skewness []float64 // Skewness
+	Kurtosis []float64 // Kurtosis (not excess)
+}
+
+// Mean computes the expectation of an interpolant.
+func Mean(surrogate *algorithm.Surrogate, basis Basis) []float64 {
+	return newPlan(surrogate, basis).raw(1)
+}
+
+// Variance computes the variance of an interpolant.
+func Variance(surrogate *algorithm.Surrogate, basis Basis) []float64 {
+	plan := newPlan(surrogate, basis)
+	μ, m2 := plan.raw(1), plan.raw(2)
+	variance := make([]float64, len(μ))
+	for i := range μ {
+		variance[i] = m2[i] - μ[i]*μ[i]
+	}
+	return variance
+}
+
+// Compute computes the first four moments of an interpolant. The skewness and
+// kurtosis of an output whose variance is zero are set to zero.
+//
+// The cost of computing the skewness and kurtosis grows with the third and
+// fourth powers of the number of basis functions whose supports overlap, which
+// is modest for local bases but can be prohibitive for global ones.
+func Compute(surrogate *algorithm.Surrogate, basis Basis) *Moments {
+	plan := newPlan(surrogate, basis)
+	μ, m2, m3, m4 := plan.raw(1), plan.raw(2), plan.raw(3), plan.raw(4)
+
+	no := len(μ)
+	moments := &Moments{
+		Mean:     μ,
+		Variance: make([]float64, no),
+		Skewness: make([]float64, no),
+		Kurtosis: make([]float64, no),
+	}
+	for i := 0; i < no; i++ {
+		μ := μ[i]
+		μ2 := m2[i] - μ*μ
+		μ3 := m3[i] - 3.0*μ*m2[i] + 2.0*μ*μ*μ
+		μ4 := m4[i] - 4.0*μ*m3[i] + 6.0*μ*μ*m2[i] - 3.0*μ*μ*μ*μ
+		moments.Variance[i] = μ2
+		if μ2 <= 0.0 {
+			continue
+		}
+		moments.Skewness[i] = μ3 / math.Pow(μ2, 1.5)
+		moments.Kurtosis[i] = μ4 / (μ2 * μ2)
+	}
+	return moments
+}
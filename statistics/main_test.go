@@ -0,0 +1,135 @@
+package statistics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ready-steady/adapt/algorithm/global"
+	"github.com/ready-steady/adapt/algorithm/local"
+	"github.com/ready-steady/adapt/basis/lagrange"
+	"github.com/ready-steady/adapt/basis/polynomial"
+	"github.com/ready-steady/adapt/grid/chebyshev"
+	"github.com/ready-steady/adapt/grid/equidistant"
+	"github.com/ready-steady/assert"
+)
+
+func TestComputeGlobal(t *testing.T) {
+	const (
+		ni = 2
+		no = 1
+	)
+
+	grid, basis := chebyshev.NewClosed(ni), lagrange.NewClosed(ni)
+	algorithm := global.New(ni, no, grid, basis)
+	strategy := global.NewStrategy(ni, no, grid, 1, 4, 1e-12, 1e-12)
+
+	surrogate := algorithm.Compute(func(x, y []float64) {
+		y[0] = x[0] + x[1]*x[1]
+	}, strategy)
+
+	moments := Compute(surrogate, basis)
+
+	// X and Y = Z^2 are independent with X, Z ~ U(0, 1).
+	μx, μy := 1.0/2.0, 1.0/3.0
+	σx2, σy2 := 1.0/12.0, 1.0/5.0-μy*μy
+	μx4 := 1.0 / 80.0
+	μy3 := 1.0/7.0 - 3.0*μy/5.0 + 2.0*μy*μy*μy
+	μy4 := 1.0/9.0 - 4.0*μy/7.0 + 6.0*μy*μy/5.0 - 3.0*μy*μy*μy*μy
+	σ2 := σx2 + σy2
+
+	assert.Close(moments.Mean, []float64{μx + μy}, 1e-14, t)
+	assert.Close(moments.Variance, []float64{σ2}, 1e-14, t)
+	assert.Close(moments.Skewness, []float64{μy3 / (σ2 * math.Sqrt(σ2))}, 1e-12, t)
+	assert.Close(moments.Kurtosis, []float64{(μx4 + 6.0*σx2*σy2 + μy4) / (σ2 * σ2)}, 1e-12, t)
+
+	assert.Close(Mean(surrogate, basis), moments.Mean, 1e-15, t)
+	assert.Close(Variance(surrogate, basis), moments.Variance, 1e-15, t)
+}
+
+func TestComputeOscillating(t *testing.T) {
+	const (
+		ni = 2
+		no = 1
+		nm = 200
+	)
+
+	grid, basis := chebyshev.NewClosed(ni), lagrange.NewClosed(ni)
+	algorithm := global.New(ni, no, grid, basis)
+	strategy := global.NewStrategy(ni, no, grid, 1, 4, 1e-12, 1e-12)
+
+	surrogate := algorithm.Compute(func(x, y []float64) {
+		y[0] = math.Sin(2.0*math.Pi*x[0])*math.Cos(3.0*x[1]) + x[1]
+	}, strategy)
+
+	moments := Compute(surrogate, basis)
+
+	// Compute the same moments of the surrogate using the midpoint rule.
+	points := make([]float64, 0, nm*nm*ni)
+	for i := 0; i < nm; i++ {
+		for j := 0; j < nm; j++ {
+			points = append(points, (float64(i)+0.5)/nm, (float64(j)+0.5)/nm)
+		}
+	}
+	values := algorithm.Evaluate(surrogate, points)
+	μ, μ2, μ3, μ4 := 0.0, 0.0, 0.0, 0.0
+	for _, value := range values {
+		μ += value / (nm * nm)
+	}
+	for _, value := range values {
+		Δ := value - μ
+		μ2 += Δ * Δ / (nm * nm)
+		μ3 += Δ * Δ * Δ / (nm * nm)
+		μ4 += Δ * Δ * Δ * Δ / (nm * nm)
+	}
+
+	assert.Close(moments.Mean, []float64{μ}, 1e-4, t)
+	assert.Close(moments.Variance, []float64{μ2}, 1e-4, t)
+	assert.Close(moments.Skewness, []float64{μ3 / math.Pow(μ2, 1.5)}, 1e-3, t)
+	assert.Close(moments.Kurtosis, []float64{μ4 / (μ2 * μ2)}, 1e-3, t)
+}
+
+func TestComputeLocal(t *testing.T) {
+	const (
+		ni = 2
+		no = 2
+	)
+
+	grid, basis := equidistant.NewClosed(ni), polynomial.NewClosed(ni, 1)
+	algorithm := local.New(ni, no, grid, basis)
+	strategy := local.NewStrategy(ni, no, grid, 1, 10, 1e-4)
+
+	surrogate := algorithm.Compute(func(x, y []float64) {
+		y[0] = x[0]
+		y[1] = 2.0*x[1] + 1.0
+	}, strategy)
+
+	moments := Compute(surrogate, basis)
+
+	assert.Close(moments.Mean, []float64{0.5, 2.0}, 1e-15, t)
+	assert.Close(moments.Variance, []float64{1.0 / 12.0, 4.0 / 12.0}, 1e-15, t)
+	assert.Close(moments.Skewness, []float64{0.0, 0.0}, 1e-12, t)
+	assert.Close(moments.Kurtosis, []float64{9.0 / 5.0, 9.0 / 5.0}, 1e-12, t)
+}
+
+func TestComputeConstant(t *testing.T) {
+	const (
+		ni = 2
+		no = 2
+	)
+
+	grid, basis := chebyshev.NewClosed(ni), lagrange.NewClosed(ni)
+	algorithm := global.New(ni, no, grid, basis)
+	strategy := global.NewStrategy(ni, no, grid, 1, 4, 1e-12, 1e-12)
+
+	surrogate := algorithm.Compute(func(x, y []float64) {
+		y[0] = 2.0
+		y[1] = x[0]
+	}, strategy)
+
+	moments := Compute(surrogate, basis)
+
+	assert.Close(moments.Mean, []float64{2.0, 0.5}, 1e-14, t)
+	assert.Close(moments.Variance, []float64{0.0, 1.0 / 12.0}, 1e-14, t)
+	assert.Close(moments.Skewness, []float64{0.0, 0.0}, 1e-12, t)
+	assert.Close(moments.Kurtosis, []float64{0.0, 9.0 / 5.0}, 1e-12, t)
+}
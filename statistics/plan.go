@@ -0,0 +1,146 @@
+package statistics
+
+import (
+	"github.com/ready-steady/adapt/algorithm"
+	"github.com/ready-steady/adapt/internal"
+)
+
+// plan is a means of computing raw moments of an interpolant.
+type plan struct {
+	surrogate *algorithm.Surrogate
+	basis     Basis
+
+	neighbors [][]uint
+	products  [][]float64
+	adjacent  []map[uint]bool
+}
+
+func newPlan(surrogate *algorithm.Surrogate, basis Basis) *plan {
+	return &plan{
+		surrogate: surrogate,
+		basis:     basis,
+	}
+}
+
+// raw computes the raw moment of a given order, which is at most four.
+func (self *plan) raw(order uint) []float64 {
+	ni, no, nn := self.surrogate.Inputs, self.surrogate.Outputs, self.surrogate.Nodes
+	indices, surpluses := self.surrogate.Indices, self.surrogate.Surpluses
+
+	moment := make([]float64, no)
+	index := func(i uint) []uint64 {
+		return indices[i*ni : (i+1)*ni]
+	}
+	accumulate := func(weight float64, terms ...uint) {
+		for k := uint(0); k < no; k++ {
+			value := weight
+			for _, i := range terms {
+				value *= surpluses[i*no+k]
+			}
+			moment[k] += value
+		}
+	}
+
+	switch order {
+	case 1:
+		for i := uint(0); i < nn; i++ {
+			accumulate(self.basis.IntegrateProduct(index(i)), i)
+		}
+	case 2:
+		self.connect()
+		for i := uint(0); i < nn; i++ {
+			for k, j := range self.neighbors[i] {
+				accumulate(self.products[i][k]*multiplicity(i, j), i, j)
+			}
+		}
+	case 3:
+		self.connect()
+		for i := uint(0); i < nn; i++ {
+			for _, j := range self.neighbors[i] {
+				for _, k := range self.neighbors[j] {
+					if !self.adjacent[i][k] {
+						continue
+					}
+					weight := self.basis.IntegrateProduct(index(i), index(j), index(k))
+					accumulate(weight*multiplicity(i, j, k), i, j, k)
+				}
+			}
+		}
+	case 4:
+		self.connect()
+		for i := uint(0); i < nn; i++ {
+			for _, j := range self.neighbors[i] {
+				for _, k := range self.neighbors[j] {
+					if !self.adjacent[i][k] {
+						continue
+					}
+					for _, l := range self.neighbors[k] {
+						if !self.adjacent[i][l] || !self.adjacent[j][l] {
+							continue
+						}
+						weight := self.basis.IntegrateProduct(index(i), index(j),
+							index(k), index(l))
+						accumulate(weight*multiplicity(i, j, k, l), i, j, k, l)
+					}
+				}
+			}
+		}
+	default:
+		panic("the order is not supported")
+	}
+
+	return moment
+}
+
+// connect finds the pairs of basis functions with overlapping supports as
+// reported by the basis. For each basis function, only the neighbors with
+// larger or equal positions are kept so that each combination is visited once.
+// Since the supports are boxes, several basis functions have a common support
+// if and only if each pair of them does.
+func (self *plan) connect() {
+	if self.neighbors != nil {
+		return
+	}
+
+	ni, nn := self.surrogate.Inputs, self.surrogate.Nodes
+	indices := self.surrogate.Indices
+
+	self.neighbors = internal.Overlap(self.basis, indices, ni)
+	self.products = make([][]float64, nn)
+	self.adjacent = make([]map[uint]bool, nn)
+	for i := uint(0); i < nn; i++ {
+		self.adjacent[i] = make(map[uint]bool)
+	}
+	for i := uint(0); i < nn; i++ {
+		for _, j := range self.neighbors[i] {
+			product := self.basis.IntegrateProduct(indices[i*ni:(i+1)*ni],
+				indices[j*ni:(j+1)*ni])
+			self.products[i] = append(self.products[i], product)
+			self.adjacent[i][j] = true
+			self.adjacent[j][i] = true
+		}
+	}
+}
+
+// multiplicity returns the number of distinct permutations of a sorted
+// combination.
+func multiplicity(terms ...uint) float64 {
+	result, count := factorial(len(terms)), 1
+	for i := 1; i < len(terms); i++ {
+		if terms[i] == terms[i-1] {
+			count++
+		} else {
+			result /= factorial(count)
+			count = 1
+		}
+	}
+	return result / factorial(count)
+}
+
+func factorial(n int) float64 {
+	result := 1.0
+	for i := 2; i <= n; i++ {
+		result *= float64(i)
+	}
+	return result
+}